@@ -14,6 +14,7 @@ import (
 
 func main() {
 	e := echo.New()
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 
 	var server generated.ServerInterface = newServer()
 
@@ -9,15 +9,127 @@
 CREATE TABLE estates (
 	id UUID PRIMARY KEY,
 	width INT NOT NULL CHECK ( width > 0 AND width <= 50000 ),
-	length INT NOT NULL CHECK ( length > 0 AND length <= 50000 )
+	length INT NOT NULL CHECK ( length > 0 AND length <= 50000 ),
+	latitude DOUBLE PRECISION CHECK ( latitude >= -90 AND latitude <= 90 ),
+	longitude DOUBLE PRECISION CHECK ( longitude >= -180 AND longitude <= 180 ),
+	bearing DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK ( bearing >= 0 AND bearing < 360 ),
+	elevation INT NOT NULL DEFAULT 0 CHECK ( elevation >= -500 AND elevation <= 9000 ),
+	max_altitude INT CHECK ( max_altitude > 0 AND max_altitude <= 1000 ),
+	CHECK ( (latitude IS NULL) = (longitude IS NULL) )
+);
+
+-- THIS IS SCRIPT FOR CREATING ESTATE PLOTS TABLE
+-- A plot holds at most one tree or obstacle. Trees and obstacles both claim
+-- their plot here, so they share one occupancy rule.
+CREATE TABLE estate_plots (
+	estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+	x INT NOT NULL CHECK ( x > 0 ),
+	y INT NOT NULL CHECK ( y > 0 ),
+	occupant VARCHAR(20) NOT NULL CHECK ( occupant IN ('tree', 'obstacle') ),
+	PRIMARY KEY (estate_id, x, y),
+	UNIQUE (estate_id, x, y, occupant)
+);
+
+-- THIS IS SCRIPT FOR CREATING PLOT ELEVATIONS TABLE
+-- Only the plots whose ground lies off the estate elevation are stored.
+CREATE TABLE plot_elevations (
+	estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+	x INT NOT NULL CHECK ( x > 0 ),
+	y INT NOT NULL CHECK ( y > 0 ),
+	elevation INT NOT NULL CHECK ( elevation >= -500 AND elevation <= 9000 ),
+	PRIMARY KEY (estate_id, x, y)
 );
 
 -- THIS IS SCRIPT FOR CREATING TREES TABLE
+-- The unique key on (estate_id, x, y) also serves the queries for the trees
+-- inside an area of an estate and the listing ordered by position. The index
+-- on (estate_id, height, x, y) serves the listing filtered or ordered by
+-- height.
 CREATE TABLE trees (
     id UUID PRIMARY KEY,
     estate_id UUID REFERENCES estates(id) ON DELETE CASCADE,
 	x INT NOT NULL CHECK ( x > 0 ),
 	y INT NOT NULL CHECK ( y > 0 ),
 	height INT NOT NULL CHECK ( height >= 1 AND height <= 30 ),
-	UNIQUE (estate_id, x, y)
-);
\ No newline at end of file
+	needs_inspection BOOLEAN NOT NULL DEFAULT FALSE,
+	occupant VARCHAR(20) NOT NULL DEFAULT 'tree' CHECK ( occupant = 'tree' ),
+	UNIQUE (estate_id, x, y),
+	FOREIGN KEY (estate_id, x, y, occupant) REFERENCES estate_plots (estate_id, x, y, occupant) ON DELETE CASCADE
+);
+
+CREATE INDEX trees_estate_id_height_idx ON trees (estate_id, height, x, y);
+
+-- THIS IS SCRIPT FOR CREATING TREE MEASUREMENTS TABLE
+-- The height of a tree is its latest measurement; the tree is measured once
+-- when it is planted. The primary key serves the history of a tree.
+CREATE TABLE tree_measurements (
+	tree_id UUID NOT NULL REFERENCES trees(id) ON DELETE CASCADE,
+	height INT NOT NULL CHECK ( height >= 1 AND height <= 30 ),
+	measured_at TIMESTAMPTZ NOT NULL,
+	source VARCHAR(20) NOT NULL CHECK ( source IN ('manual', 'drone') ),
+	PRIMARY KEY (tree_id, measured_at)
+);
+
+-- THIS IS SCRIPT FOR CREATING OBSTACLES TABLE
+CREATE TABLE obstacles (
+	id UUID PRIMARY KEY,
+	estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+	x INT NOT NULL CHECK ( x > 0 ),
+	y INT NOT NULL CHECK ( y > 0 ),
+	height INT NOT NULL CHECK ( height >= 1 AND height <= 300 ),
+	kind VARCHAR(100) NOT NULL,
+	occupant VARCHAR(20) NOT NULL DEFAULT 'obstacle' CHECK ( occupant = 'obstacle' ),
+	UNIQUE (estate_id, x, y),
+	FOREIGN KEY (estate_id, x, y, occupant) REFERENCES estate_plots (estate_id, x, y, occupant) ON DELETE CASCADE
+);
+
+-- THIS IS SCRIPT FOR CREATING DRONES TABLE
+CREATE TABLE drones (
+	id UUID PRIMARY KEY,
+	model VARCHAR(100) NOT NULL,
+	cruise_speed DOUBLE PRECISION NOT NULL CHECK ( cruise_speed > 0 ),
+	climb_rate DOUBLE PRECISION NOT NULL CHECK ( climb_rate > 0 ),
+	descent_rate DOUBLE PRECISION NOT NULL CHECK ( descent_rate > 0 ),
+	endurance INT NOT NULL CHECK ( endurance > 0 ),
+	clearance INT NOT NULL DEFAULT 1 CHECK ( clearance >= 1 AND clearance <= 100 )
+);
+
+-- THIS IS SCRIPT FOR CREATING MISSIONS TABLE
+CREATE TABLE missions (
+	id UUID PRIMARY KEY,
+	estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+	drone_id UUID NOT NULL REFERENCES drones(id) ON DELETE RESTRICT,
+	planned_distance INT NOT NULL CHECK ( planned_distance >= 0 ),
+	tree_count INT NOT NULL CHECK ( tree_count >= 0 ),
+	scheduled_at TIMESTAMPTZ NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK ( status IN ('planned', 'in-flight', 'completed', 'aborted') ),
+	actual_distance INT CHECK ( actual_distance >= 0 ),
+	layout_digest CHAR(64) NOT NULL
+);
+
+CREATE INDEX missions_estate_id_scheduled_at_idx ON missions (estate_id, scheduled_at);
+CREATE INDEX missions_drone_id_idx ON missions (drone_id);
+
+-- THIS IS SCRIPT FOR CREATING TELEMETRY SAMPLES TABLE
+CREATE TABLE telemetry_samples (
+	mission_id UUID NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+	seq INT NOT NULL CHECK ( seq >= 0 ),
+	recorded_at TIMESTAMPTZ NOT NULL,
+	x DOUBLE PRECISION NOT NULL,
+	y DOUBLE PRECISION NOT NULL,
+	altitude DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (mission_id, seq)
+);
+
+-- THIS IS SCRIPT FOR CREATING NO FLY ZONES TABLE
+CREATE TABLE no_fly_zones (
+	id UUID PRIMARY KEY,
+	estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+	from_x INT NOT NULL CHECK ( from_x > 0 ),
+	from_y INT NOT NULL CHECK ( from_y > 0 ),
+	to_x INT NOT NULL,
+	to_y INT NOT NULL,
+	CHECK ( to_x >= from_x AND to_y >= from_y )
+);
+
+CREATE INDEX no_fly_zones_estate_id_idx ON no_fly_zones (estate_id);
@@ -0,0 +1,87 @@
+package droneplan
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCeiling(t *testing.T) {
+	trees := []Tree{
+		{X: 2, Y: 2, Height: 30},
+		{X: 3, Y: 1, Height: 19},
+		{X: 1, Y: 3, Height: 20},
+	}
+	obstacles := []Obstacle{{X: 3, Y: 3, Height: 60}}
+	zone := Area{From: Plot{X: 3, Y: 3}, To: Plot{X: 3, Y: 3}}
+
+	t.Run("Count", func(t *testing.T) {
+		assert.Equal(t, 3, CountAboveCeiling(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles, MaxAltitude: 20}))
+		assert.Equal(t, 1, CountAboveCeiling(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles, MaxAltitude: 40}))
+		assert.Equal(t, 0, CountAboveCeiling(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles}))
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		plan := New(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles, MaxAltitude: 20})
+		unlimited := New(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles})
+
+		// A 19 metre tree keeps the drone at the ceiling, a 20 metre one
+		// forces it a metre above.
+		assert.Equal(t, []Plot{{X: 2, Y: 2}, {X: 1, Y: 3}, {X: 3, Y: 3}}, plan.AboveCeiling())
+		assert.Empty(t, plan.SkippedAboveCeiling())
+		assert.Equal(t, 0, plan.SkippedPlots())
+		assert.Equal(t, unlimited.Distance(), plan.Distance())
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		plan := New(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles, NoFlyZones: []Area{zone}, MaxAltitude: 20, SkipAboveCeiling: true})
+		zones := New(Options{Width: 3, Length: 3, Trees: trees, Obstacles: obstacles, NoFlyZones: []Area{
+			zone,
+			{From: Plot{X: 2, Y: 2}, To: Plot{X: 2, Y: 2}},
+			{From: Plot{X: 1, Y: 3}, To: Plot{X: 1, Y: 3}},
+		}})
+
+		// The obstacle lies in a no-fly zone and is skipped for the zone.
+		// Plot (2, 3) is walled in and cannot be reached.
+		assert.Equal(t, []Plot{{X: 2, Y: 2}, {X: 1, Y: 3}}, plan.SkippedAboveCeiling())
+		assert.Equal(t, []Area{{From: Plot{X: 2, Y: 3}, To: Plot{X: 2, Y: 3}}}, plan.Unreachable())
+		assert.Equal(t, 4, plan.SkippedPlots())
+		assert.Equal(t, zones.Distance(), plan.Distance())
+		assert.Empty(t, plan.AboveCeiling())
+		for position := 0; position < plan.path.len(); position++ {
+			assert.NotContains(t, plan.SkippedAboveCeiling(), plan.path.plot(position))
+		}
+	})
+
+	t.Run("Skip_Inside_The_Region", func(t *testing.T) {
+		region := Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 3, Y: 1}}
+		plan := New(Options{Width: 3, Length: 3, Trees: trees, MaxAltitude: 20, SkipAboveCeiling: true, Region: &region})
+
+		assert.Empty(t, plan.SkippedAboveCeiling())
+		assert.Equal(t, 0, plan.SkippedPlots())
+	})
+
+	t.Run("Flag_Only_Plots_Flown_Over", func(t *testing.T) {
+		region := Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 3, Y: 1}}
+		plan := New(Options{Width: 3, Length: 3, Trees: trees, NoFlyZones: []Area{zone}, Obstacles: obstacles, MaxAltitude: 20, Region: &region})
+
+		assert.Empty(t, plan.AboveCeiling())
+	})
+}
+
+func BenchmarkNewSkipAboveCeiling(b *testing.B) {
+	for _, count := range []int{25, 50, 100} {
+		trees := randomTrees(rand.New(rand.NewSource(1)), 1000, 1000, count)
+		for i := range trees {
+			trees[i].Height = 20
+		}
+
+		b.Run(fmt.Sprintf("Skipped_%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				New(Options{Width: 1000, Length: 1000, Trees: trees, MaxAltitude: 10, SkipAboveCeiling: true})
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package droneplan
+
+import "errors"
+
+// ErrEnduranceTooShort is returned when the drone battery cannot carry it
+// from one plot to the next and back to the ground, so swapping batteries
+// never gets it past that plot.
+var ErrEnduranceTooShort = errors.New("drone endurance is too short to survey the estate")
+
+// Drone is the aircraft flying the plan.
+type Drone struct {
+	// CruiseSpeed is the horizontal speed in metres per second.
+	CruiseSpeed float64
+	// ClimbRate and DescentRate are the vertical speeds in metres per
+	// second.
+	ClimbRate   float64
+	DescentRate float64
+	// Endurance is the distance in metres the drone flies on one battery.
+	Endurance int
+}
+
+// Flight is the plan flown by a drone, landing to swap batteries whenever
+// the one it carries would not last to the next landing.
+type Flight struct {
+	// Distance is the total distance in metres flown, the landings and
+	// take-offs for the battery swaps included.
+	Distance int
+	// Duration is the time in seconds spent in the air. The time spent on
+	// the ground swapping batteries is not counted.
+	Duration float64
+	// Swaps is the number of times the drone lands to swap its battery.
+	Swaps int
+}
+
+// Fly works out the flight of the drone over the whole plan.
+func (p *Plan) Fly(drone Drone) (Flight, error) {
+	return p.fly(0, p.path.len()-1, drone)
+}
+
+// FlySection works out the flight of the drone over one section of the
+// plan.
+func (p *Plan) FlySection(section Section, drone Drone) (Flight, error) {
+	return p.fly(section.start, section.end, drone)
+}
+
+// fly follows the drone from the plot at position start to the plot at
+// position end. On every battery the drone takes off, flies as far as the
+// battery lets it land again, and lands there for the next swap.
+//
+// Along an empty stretch every battery covers the same number of plots, so
+// those legs are counted in one step rather than flown one by one.
+func (p *Plan) fly(start, end int, drone Drone) (flight Flight, err error) {
+	// Plots flown at the clearance height on one battery, take-off and
+	// landing included.
+	step := (drone.Endurance - 2*p.clearance) / PlotSize
+
+	position := start
+	for {
+		if i := p.lastStopAt(position); step > 0 && (i < 0 || p.stops[i].position != position) {
+			next := end + 1
+			if i+1 < len(p.stops) && p.stops[i+1].position < next {
+				next = p.stops[i+1].position
+			}
+
+			if legs := (next - 1 - position) / step; legs > 0 {
+				flight.Swaps += legs
+				flight.Distance += legs * (2*p.clearance + PlotSize*step)
+				position += legs * step
+				if position == end {
+					flight.Swaps--
+					break
+				}
+			}
+		}
+
+		// The battery lasts to the plots whose sectionDistance from here is
+		// within the endurance.
+		landing := p.lastLanding(position, end, drone.Endurance-p.heightAt(position)+p.distanceAt(position))
+		if landing < position || (landing == position && landing < end) {
+			return Flight{}, ErrEnduranceTooShort
+		}
+
+		flight.Distance += p.sectionDistance(position, landing)
+		position = landing
+		if position == end {
+			break
+		}
+		flight.Swaps++
+	}
+
+	// Every flight starts and ends on the ground, so the drone climbs as
+	// much as it descends, give or take the rise of the ground from the
+	// first plot to the last.
+	horizontal := PlotSize * (end - start)
+	vertical := float64(flight.Distance - horizontal)
+	rise := float64(p.groundOf(p.path.plot(end)) - p.groundOf(p.path.plot(start)))
+	flight.Duration = float64(horizontal)/drone.CruiseSpeed + (vertical+rise)/2/drone.ClimbRate + (vertical-rise)/2/drone.DescentRate
+
+	return
+}
@@ -0,0 +1,144 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFly(t *testing.T) {
+	drone := Drone{CruiseSpeed: 10, ClimbRate: 1, DescentRate: 2, Endurance: 1000}
+
+	testCases := []struct {
+		name      string
+		width     int
+		length    int
+		trees     []Tree
+		clearance int
+		endurance int
+		flight    Flight
+		err       error
+	}{
+		{
+			name:      "Fly_Without_Swaps",
+			width:     5,
+			length:    1,
+			trees:     rowTrees,
+			endurance: 1000,
+			flight:    Flight{Distance: 54, Duration: 4 + 7 + 3.5},
+		},
+		{
+			name:      "Fly_With_One_Swap",
+			width:     5,
+			length:    1,
+			trees:     rowTrees,
+			endurance: 40,
+			flight:    Flight{Distance: 62, Duration: 4 + 11 + 5.5, Swaps: 1},
+		},
+		{
+			name:      "Fly_Empty_Estate_With_Swaps",
+			width:     10,
+			length:    10,
+			endurance: 102,
+			flight:    Flight{Distance: 990 + 2*10, Duration: 99 + 10 + 5, Swaps: 9},
+		},
+		{
+			name:      "Fly_With_Clearance",
+			width:     5,
+			length:    1,
+			trees:     rowTrees,
+			clearance: 3,
+			endurance: 1000,
+			flight:    Flight{Distance: 58, Duration: 4 + 9 + 4.5},
+		},
+		{
+			name:      "Fly_Endurance_Too_Short",
+			width:     5,
+			length:    1,
+			trees:     rowTrees,
+			endurance: 15,
+			err:       ErrEnduranceTooShort,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := New(Options{Width: tc.width, Length: tc.length, Trees: tc.trees, Clearance: tc.clearance})
+			drone.Endurance = tc.endurance
+
+			flight, err := plan.Fly(drone)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.flight, flight)
+		})
+	}
+}
+
+func TestFlyMatchesPlotByPlotFlight(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		width := random.Intn(8) + 1
+		length := random.Intn(8) + 1
+		trees := randomTrees(random, width, length, random.Intn(width*length/4+1))
+		drone := Drone{CruiseSpeed: 1, ClimbRate: 1, DescentRate: 1, Endurance: random.Intn(200) + 1}
+
+		flight, err := New(Options{Width: width, Length: length, Trees: trees}).Fly(drone)
+		distance, swaps, ok := swapPlotByPlot(flyPlotByPlot(width, length, trees), drone.Endurance)
+		if !ok {
+			assert.Equal(t, ErrEnduranceTooShort, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, distance, flight.Distance)
+		assert.Equal(t, swaps, flight.Swaps)
+	}
+}
+
+func TestFlySection(t *testing.T) {
+	plan := New(Options{Width: 5, Length: 1, Trees: rowTrees})
+	sections := plan.Split(2)
+
+	flight, err := plan.FlySection(sections[1], Drone{CruiseSpeed: 10, ClimbRate: 1, DescentRate: 1, Endurance: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, Flight{Distance: sections[1].Distance, Duration: 1 + 10}, flight)
+}
+
+// swapPlotByPlot lands the drone for a new battery at the last plot it can
+// reach on the current one, using the waypoints of flyPlotByPlot. It
+// reports false when the drone gets stuck on a plot.
+func swapPlotByPlot(waypoints []Waypoint, endurance int) (distance, swaps int, ok bool) {
+	plots := waypoints[1 : len(waypoints)-1]
+	cost := func(from, to int) int {
+		return plots[from].Altitude + plots[to].Distance - plots[from].Distance + plots[to].Altitude
+	}
+
+	position := 0
+	for {
+		landing := position
+		for landing+1 < len(plots) && cost(position, landing+1) <= endurance {
+			landing++
+		}
+		if cost(position, landing) > endurance || (landing == position && landing < len(plots)-1) {
+			return 0, 0, false
+		}
+
+		distance += cost(position, landing)
+		position = landing
+		if position == len(plots)-1 {
+			return distance, swaps, true
+		}
+		swaps++
+	}
+}
+
+func BenchmarkFly(b *testing.B) {
+	plan := New(Options{Width: 50000, Length: 50000, Trees: randomTrees(rand.New(rand.NewSource(1)), 50000, 50000, 5000)})
+	drone := Drone{CruiseSpeed: 10, ClimbRate: 3, DescentRate: 2, Endurance: 20000}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plan.Fly(drone)
+	}
+}
@@ -0,0 +1,36 @@
+package droneplan
+
+import "github.com/pebruwantoro/technical-test-sawitpro/geo"
+
+// Features returns the plan placed on the globe by reference: the flight
+// path as a line, and the take-off and landing points. Altitudes are given
+// above the ground under every point.
+func (p *Plan) Features(reference geo.Reference) []geo.Feature {
+	route := p.Route()
+
+	line := make([]geo.Coordinate, 0, len(route))
+	for _, waypoint := range route {
+		ground := p.groundOf(Plot{X: waypoint.X, Y: waypoint.Y})
+		line = append(line, reference.Plot(waypoint.X, waypoint.Y, PlotSize, float64(waypoint.Altitude-ground)))
+	}
+	takeOff := line[0]
+	landing := line[len(line)-1]
+
+	return []geo.Feature{
+		{
+			Name:       "Drone Path",
+			Properties: map[string]interface{}{"distance": p.Distance()},
+			Line:       line,
+		},
+		{
+			Name:       "Take Off",
+			Properties: map[string]interface{}{"x": route[0].X, "y": route[0].Y},
+			Point:      &takeOff,
+		},
+		{
+			Name:       "Landing",
+			Properties: map[string]interface{}{"x": route[len(route)-1].X, "y": route[len(route)-1].Y},
+			Point:      &landing,
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package droneplan
+
+import (
+	"testing"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatures(t *testing.T) {
+	reference := geo.Reference{Latitude: -1.5, Longitude: 102.1}
+	features := New(Options{Width: 3, Length: 2}).Features(reference)
+
+	assert.Len(t, features, 3)
+	assert.Equal(t, "Drone Path", features[0].Name)
+	assert.Equal(t, 52, features[0].Properties["distance"])
+	assert.Equal(t, []geo.Coordinate{
+		reference.Plot(1, 1, PlotSize, 0),
+		reference.Plot(1, 1, PlotSize, 1),
+		reference.Plot(3, 1, PlotSize, 1),
+		reference.Plot(3, 2, PlotSize, 1),
+		reference.Plot(1, 2, PlotSize, 1),
+		reference.Plot(1, 2, PlotSize, 0),
+	}, features[0].Line)
+	assert.Equal(t, reference.Plot(1, 1, PlotSize, 0), *features[1].Point)
+	assert.Equal(t, reference.Plot(1, 2, PlotSize, 0), *features[2].Point)
+}
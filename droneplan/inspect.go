@@ -0,0 +1,49 @@
+package droneplan
+
+// Inspection is the extra flight for the close inspection of the trees
+// flagged for it, on top of the survey.
+type Inspection struct {
+	// Stops is the number of trees the drone hovers over.
+	Stops int
+	// Distance is the extra distance in metres flown descending to the
+	// trees and climbing back.
+	Distance int
+	// Duration is the extra time in seconds spent hovering, descending and
+	// climbing back.
+	Duration float64
+}
+
+// Inspect works out the hover stops over the trees flagged for
+// inspection. Above every flagged tree it surveys, the drone descends by
+// descent metres, but never lower than its clearance above the top of the
+// tallest tree on the plot, hovers for hover seconds and climbs back to
+// carry on with the survey. The drone already cruises at that clearance
+// over a tree, so it only descends where it flies higher than that. The
+// descents and climbs are only timed for a known drone; without one the
+// duration is the hover time alone.
+func (p *Plan) Inspect(descent int, hover float64, drone *Drone) Inspection {
+	var inspection Inspection
+	tallest := make(map[Plot]int)
+	for _, tree := range p.trees {
+		plot := Plot{X: tree.X, Y: tree.Y}
+		tallest[plot] = max(tallest[plot], tree.Height)
+	}
+	seen := make(map[Plot]bool)
+	for _, tree := range p.trees {
+		plot := Plot{X: tree.X, Y: tree.Y}
+		if !tree.Inspect || seen[plot] || !p.path.surveys(plot) {
+			continue
+		}
+		seen[plot] = true
+
+		floor := p.groundOf(plot) + tallest[plot] + p.clearance
+		drop := max(0, min(descent, p.occupied[plot]-floor))
+		inspection.Stops++
+		inspection.Distance += 2 * drop
+		inspection.Duration += hover
+		if drone != nil {
+			inspection.Duration += float64(drop)/drone.DescentRate + float64(drop)/drone.ClimbRate
+		}
+	}
+	return inspection
+}
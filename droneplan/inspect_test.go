@@ -0,0 +1,62 @@
+package droneplan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	trees := []Tree{
+		{X: 2, Y: 1, Height: 10, Inspect: true},
+		{X: 3, Y: 1, Height: 3, Inspect: true},
+		{X: 4, Y: 1, Height: 20},
+		{X: 3, Y: 3, Height: 8, Inspect: true},
+	}
+	zone := []Area{{From: Plot{X: 3, Y: 3}, To: Plot{X: 3, Y: 3}}}
+	drone := Drone{CruiseSpeed: 10, ClimbRate: 2, DescentRate: 4, Endurance: 10000}
+
+	testCases := []struct {
+		name       string
+		descent    int
+		hover      float64
+		drone      *Drone
+		inspection Inspection
+	}{
+		{
+			// The tree inside the no-fly zone is not surveyed, and the
+			// drone already cruises at its clearance above the treetops,
+			// so it hovers without descending.
+			name:       "Inspect_Without_A_Drone",
+			descent:    5,
+			hover:      10,
+			inspection: Inspection{Stops: 2, Duration: 20},
+		},
+		{
+			name:       "Inspect_With_A_Drone",
+			descent:    5,
+			hover:      10,
+			drone:      &drone,
+			inspection: Inspection{Stops: 2, Duration: 20},
+		},
+		{
+			// Descending further than the trees are tall would take the
+			// drone through the canopy down to the ground.
+			name:       "Inspect_Descent_Above_The_Tree_Height",
+			descent:    15,
+			hover:      10,
+			drone:      &drone,
+			inspection: Inspection{Stops: 2, Duration: 20},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := New(Options{Width: 5, Length: 3, Trees: trees, NoFlyZones: zone})
+			assert.Equal(t, tc.inspection, plan.Inspect(tc.descent, tc.hover, tc.drone))
+		})
+	}
+
+	plan := New(Options{Width: 5, Length: 3})
+	assert.Equal(t, Inspection{}, plan.Inspect(5, 10, nil))
+}
@@ -0,0 +1,466 @@
+package droneplan
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// layout lays the path over the estate, keeping clear of the no-fly zones,
+// which must lie inside the estate. The plots outside the zones are split into
+// rectangular blocks: the estate is cut into strips of columns along the
+// zone edges, and every run of free rows inside a strip is a block. The
+// drone surveys the strips from west to east, alternately northwards and
+// southwards, and ferries around the zones from the end of one block to the
+// nearest corner of the next one.
+//
+// Blocks the drone cannot reach from the rest of the estate without
+// crossing a zone are left out and returned as unreachable. When the zones
+// split the estate apart, the drone surveys the part with the most plots.
+//
+// Given a region, the drone surveys only the plots inside it. It takes off
+// from the estate entry, ferries to the region, and ferries back to the
+// entry to land once it has surveyed the region. The blocks it cannot reach
+// from the entry are unreachable.
+//
+// newBlock lays out the survey of a block from one of its corners.
+func layout(estate Area, zones []Area, region *Area, entry Plot, newBlock func(area Area, start Plot) segment) (segments []segment, unreachable []Area) {
+	survey := estate
+	if region != nil {
+		survey = *region
+	}
+	var inside []Area
+	for _, zone := range zones {
+		if zone, ok := clip(zone, survey); ok {
+			inside = append(inside, zone)
+		}
+	}
+	var cells [][]Area
+	for _, strip := range strips(survey, inside) {
+		cells = append(cells, freeCells(strip, survey, inside))
+	}
+
+	// The drone ferries from the end of every block, so whatever plot a
+	// block ends at must lie on the grid.
+	lines := []Area{survey}
+	for _, strip := range cells {
+		for _, cell := range strip {
+			for _, corner := range []Plot{cell.From, {X: cell.To.X, Y: cell.From.Y}, {X: cell.From.X, Y: cell.To.Y}, cell.To} {
+				b := newBlock(cell, corner)
+				end := b.plot(b.len() - 1)
+				lines = append(lines, Area{From: end, To: end})
+			}
+		}
+	}
+	g := newGrid(estate, zones, lines)
+
+	components := g.components()
+	best := -1
+	if region != nil {
+		// Keep the blocks the drone reaches from the entry.
+		best = components[g.node(entry)]
+	} else {
+		// Keep the blocks of the largest part of the estate.
+		plots := make(map[int]int)
+		for _, strip := range cells {
+			for _, cell := range strip {
+				plots[components[g.node(cell.From)]] += cell.width() * cell.length()
+			}
+		}
+		for component, count := range plots {
+			if best < 0 || count > plots[best] || (count == plots[best] && component < best) {
+				best = component
+			}
+		}
+	}
+
+	var blocks []Area
+	northwards := true
+	for _, strip := range cells {
+		var reachable []Area
+		for _, cell := range strip {
+			if components[g.node(cell.From)] != best {
+				unreachable = append(unreachable, cell)
+				continue
+			}
+			reachable = append(reachable, cell)
+		}
+		if len(reachable) == 0 {
+			continue
+		}
+
+		if !northwards {
+			for i, j := 0, len(reachable)-1; i < j; i, j = i+1, j-1 {
+				reachable[i], reachable[j] = reachable[j], reachable[i]
+			}
+		}
+		blocks = append(blocks, reachable...)
+		northwards = !northwards
+	}
+
+	var end Plot
+	for i, area := range blocks {
+		corners := []Plot{
+			area.From,
+			{X: area.To.X, Y: area.From.Y},
+			{X: area.From.X, Y: area.To.Y},
+			area.To,
+		}
+
+		// The first block of the estate starts from the corner nearest
+		// the entry, the others from the corner nearest the end of the
+		// previous block. The first block of a region is ferried to from
+		// the entry.
+		var start Plot
+		if i == 0 && region == nil {
+			start = corners[0]
+			for _, corner := range corners[1:] {
+				if corner.X+corner.Y < start.X+start.Y {
+					start = corner
+				}
+			}
+		} else {
+			if i == 0 {
+				end = entry
+			}
+			g.shortestPaths(end, corners...)
+			start = corners[0]
+			for _, corner := range corners[1:] {
+				if g.distance(g.node(corner)) < g.distance(g.node(start)) {
+					start = corner
+				}
+			}
+			if i == 0 && start != entry {
+				segments = append(segments, line{from: entry, step: Plot{X: 1}, n: 1})
+			}
+			segments = append(segments, ferry(g.route(end, start))...)
+		}
+
+		b := newBlock(area, start)
+		segments = append(segments, b)
+		end = b.plot(b.len() - 1)
+	}
+
+	if region != nil && len(blocks) > 0 && end != entry {
+		g.shortestPaths(end, entry)
+		segments = append(segments, ferry(g.route(end, entry))...)
+		segments = append(segments, line{from: entry, step: Plot{X: 1}, n: 1})
+	}
+
+	return segments, unreachable
+}
+
+// strips returns the strips of columns the no-fly zones cut the area into.
+// Inside a strip every row is either wholly free or wholly closed. The
+// zones must lie inside the area.
+func strips(area Area, zones []Area) []Area {
+	edges := map[int]bool{area.From.X: true, area.To.X + 1: true}
+	for _, zone := range zones {
+		edges[zone.From.X] = true
+		edges[zone.To.X+1] = true
+	}
+
+	xs := make([]int, 0, len(edges))
+	for x := range edges {
+		xs = append(xs, x)
+	}
+	sort.Ints(xs)
+
+	strips := make([]Area, 0, len(xs)-1)
+	for i := 1; i < len(xs); i++ {
+		strips = append(strips, Area{From: Plot{X: xs[i-1]}, To: Plot{X: xs[i] - 1}})
+	}
+	return strips
+}
+
+// freeCells returns the runs of free rows of a strip of the area, from
+// south to north.
+func freeCells(strip, area Area, zones []Area) []Area {
+	var closed []Area
+	for _, zone := range zones {
+		if zone.From.X <= strip.From.X && zone.To.X >= strip.To.X {
+			closed = append(closed, zone)
+		}
+	}
+	sort.Slice(closed, func(i, j int) bool {
+		return closed[i].From.Y < closed[j].From.Y
+	})
+
+	var cells []Area
+	y := area.From.Y
+	for _, zone := range closed {
+		if zone.From.Y > y {
+			cells = append(cells, Area{From: Plot{X: strip.From.X, Y: y}, To: Plot{X: strip.To.X, Y: zone.From.Y - 1}})
+		}
+		if zone.To.Y+1 > y {
+			y = zone.To.Y + 1
+		}
+	}
+	if y <= area.To.Y {
+		cells = append(cells, Area{From: Plot{X: strip.From.X, Y: y}, To: Plot{X: strip.To.X, Y: area.To.Y}})
+	}
+	return cells
+}
+
+// grid is a coarse grid over the estate holding every column and row where
+// a shortest flight around the no-fly zones may turn: the estate edges and
+// the zone edges together with the free lines just outside them, and the
+// lines through the given areas. Between two neighbouring grid lines no zone
+// starts or ends, so the drone can fly from one grid node to the next
+// whenever both nodes are free.
+//
+// The grid keeps the state of its last search for shortest flights, so
+// that the searches of the ferry flights between blocks reuse it and only
+// reset the nodes they reach.
+type grid struct {
+	xs []int
+	ys []int
+	// closed marks the nodes inside a no-fly zone.
+	closed []bool
+
+	distances []int
+	previous  []int
+	// reached holds the search that last reached every node, so a node
+	// the current search has not reached is at an infinite distance.
+	reached []int
+	search  int
+	queue   nodeQueue
+}
+
+func newGrid(estate Area, zones []Area, lines []Area) *grid {
+	edges := append(lines, zones...)
+	g := &grid{
+		xs: gridLines(estate.To.X, edges, func(p Plot) int { return p.X }),
+		ys: gridLines(estate.To.Y, edges, func(p Plot) int { return p.Y }),
+	}
+
+	nodes := len(g.xs) * len(g.ys)
+	g.closed = make([]bool, nodes)
+	g.distances = make([]int, nodes)
+	g.previous = make([]int, nodes)
+	g.reached = make([]int, nodes)
+
+	// The zone edges lie on grid lines, so the nodes of a zone are the
+	// ones between the lines of its edges.
+	for _, zone := range zones {
+		fromI, toI := sort.SearchInts(g.xs, zone.From.X), sort.SearchInts(g.xs, zone.To.X)
+		fromJ, toJ := sort.SearchInts(g.ys, zone.From.Y), sort.SearchInts(g.ys, zone.To.Y)
+		for i := fromI; i <= toI; i++ {
+			for j := fromJ; j <= toJ; j++ {
+				g.closed[i*len(g.ys)+j] = true
+			}
+		}
+	}
+	return g
+}
+
+func gridLines(size int, areas []Area, coordinate func(Plot) int) []int {
+	lines := map[int]bool{1: true, size: true}
+	for _, area := range areas {
+		from, to := coordinate(area.From), coordinate(area.To)
+		for _, line := range []int{from - 1, from, to, to + 1} {
+			if line >= 1 && line <= size {
+				lines[line] = true
+			}
+		}
+	}
+
+	sorted := make([]int, 0, len(lines))
+	for line := range lines {
+		sorted = append(sorted, line)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+// node returns the grid node on the plot, which must lie on grid lines.
+func (g *grid) node(plot Plot) int {
+	return sort.SearchInts(g.xs, plot.X)*len(g.ys) + sort.SearchInts(g.ys, plot.Y)
+}
+
+func (g *grid) plot(node int) Plot {
+	return Plot{X: g.xs[node/len(g.ys)], Y: g.ys[node%len(g.ys)]}
+}
+
+// neighbours calls visit for every free node next to the given one, with
+// the distance in plots between them.
+func (g *grid) neighbours(node int, visit func(next, distance int)) {
+	i, j := node/len(g.ys), node%len(g.ys)
+	try := func(ni, nj int) {
+		if ni < 0 || ni >= len(g.xs) || nj < 0 || nj >= len(g.ys) {
+			return
+		}
+		next := ni*len(g.ys) + nj
+		if !g.closed[next] {
+			visit(next, abs(g.xs[ni]-g.xs[i])+abs(g.ys[nj]-g.ys[j]))
+		}
+	}
+	try(i-1, j)
+	try(i+1, j)
+	try(i, j-1)
+	try(i, j+1)
+}
+
+// components labels every free node with the connected part of the estate
+// it belongs to.
+func (g *grid) components() []int {
+	labels := make([]int, len(g.xs)*len(g.ys))
+	for node := range labels {
+		labels[node] = -1
+	}
+
+	for node := range labels {
+		if labels[node] >= 0 || g.closed[node] {
+			continue
+		}
+
+		labels[node] = node
+		queue := []int{node}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			g.neighbours(current, func(next, _ int) {
+				if labels[next] < 0 {
+					labels[next] = node
+					queue = append(queue, next)
+				}
+			})
+		}
+	}
+
+	return labels
+}
+
+// shortestPaths searches the shortest flights from the plot until the
+// flights to the nearest of the targets, which must lie on grid lines, are
+// known, or every node the drone can reach is. The flights found are read
+// with distance and route until the next search: the nearest targets are
+// at their distance, the others farther.
+//
+// The search heads for the targets: it takes the nodes in order of the
+// distance flown to them plus the distance left in a straight line to the
+// nearest target, which the drone can never beat, so the first target
+// taken is the nearest one.
+func (g *grid) shortestPaths(from Plot, targets ...Plot) {
+	g.search++
+	isTarget := make(map[int]bool, len(targets))
+	for _, target := range targets {
+		isTarget[g.node(target)] = true
+	}
+	left := func(node int) int {
+		plot := g.plot(node)
+		nearest := -1
+		for _, target := range targets {
+			if d := abs(target.X-plot.X) + abs(target.Y-plot.Y); nearest < 0 || d < nearest {
+				nearest = d
+			}
+		}
+		return nearest
+	}
+
+	start := g.node(from)
+	g.reach(start, 0, -1)
+	g.queue = append(g.queue[:0], queued{node: start, estimate: left(start)})
+	nearest := -1
+	for g.queue.Len() > 0 {
+		current := heap.Pop(&g.queue).(queued)
+		if current.distance > g.distances[current.node] {
+			continue
+		}
+		// Every node that may be as near as the nearest target is
+		// searched, so that the targets tied with it are known too.
+		if nearest >= 0 && current.estimate > nearest {
+			break
+		}
+		if nearest < 0 && isTarget[current.node] {
+			nearest = current.distance
+		}
+		g.neighbours(current.node, func(next, distance int) {
+			if d := current.distance + distance; d < g.distance(next) {
+				g.reach(next, d, current.node)
+				heap.Push(&g.queue, queued{node: next, distance: d, estimate: d + left(next)})
+			}
+		})
+	}
+}
+
+// reach records the shortest flight found so far to a node.
+func (g *grid) reach(node, distance, previous int) {
+	g.reached[node] = g.search
+	g.distances[node] = distance
+	g.previous[node] = previous
+}
+
+// distance returns the flying distance in plots of the last search to a
+// node, or an infinite one when the search has not reached it.
+func (g *grid) distance(node int) int {
+	if g.reached[node] != g.search {
+		return int(^uint(0) >> 1)
+	}
+	return g.distances[node]
+}
+
+// route returns the grid plots the shortest flight of the last search from
+// one plot to a target turns at, both ends included.
+func (g *grid) route(from, to Plot) []Plot {
+	var plots []Plot
+	for node := g.node(to); node >= 0; node = g.previous[node] {
+		plots = append(plots, g.plot(node))
+		if node == g.node(from) {
+			break
+		}
+	}
+	for i, j := 0, len(plots)-1; i < j; i, j = i+1, j-1 {
+		plots[i], plots[j] = plots[j], plots[i]
+	}
+	return plots
+}
+
+// ferry turns the corners of a flight into straight lines. The first and
+// the last corner are left out, as the drone surveys them.
+func ferry(corners []Plot) []segment {
+	var lines []line
+	for i := 1; i < len(corners); i++ {
+		from, to := corners[i-1], corners[i]
+		step := Plot{X: sign(to.X - from.X), Y: sign(to.Y - from.Y)}
+		n := abs(to.X-from.X) + abs(to.Y-from.Y)
+
+		if last := len(lines) - 1; last >= 0 && lines[last].step == step {
+			lines[last].n += n
+			continue
+		}
+		lines = append(lines, line{from: Plot{X: from.X + step.X, Y: from.Y + step.Y}, step: step, n: n})
+	}
+
+	segments := make([]segment, 0, len(lines))
+	for i, l := range lines {
+		if i == len(lines)-1 {
+			l.n--
+		}
+		if l.n > 0 {
+			segments = append(segments, l)
+		}
+	}
+	return segments
+}
+
+// queued is a grid node waiting in a nodeQueue, with the distance flown
+// to it and the estimate of the whole flight through it.
+type queued struct {
+	node     int
+	distance int
+	estimate int
+}
+
+// nodeQueue is a priority queue of grid nodes, lowest estimate first.
+type nodeQueue []queued
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].estimate < q[j].estimate }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queued)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,213 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoFlyZones(t *testing.T) {
+	testCases := []struct {
+		name         string
+		width        int
+		length       int
+		zones        []Area
+		distance     int
+		skippedPlots int
+		unreachable  []Area
+	}{
+		{
+			name:     "NoFlyZones_Ferry_Around_A_Zone",
+			width:    3,
+			length:   3,
+			zones:    []Area{{From: Plot{X: 2, Y: 2}, To: Plot{X: 2, Y: 2}}},
+			distance: 102,
+			// 3 plots surveyed, 1 surveyed, 3 ferried, 1 surveyed and
+			// 3 surveyed.
+			skippedPlots: 1,
+		},
+		{
+			name:         "NoFlyZones_Corners_In_Any_Order",
+			width:        3,
+			length:       3,
+			zones:        []Area{{From: Plot{X: 2, Y: 3}, To: Plot{X: 2, Y: 2}}},
+			distance:     82,
+			skippedPlots: 2,
+		},
+		{
+			name:         "NoFlyZones_Zone_Outside_The_Estate_Is_Ignored",
+			width:        3,
+			length:       2,
+			zones:        []Area{{From: Plot{X: 4, Y: 1}, To: Plot{X: 6, Y: 2}}},
+			distance:     52,
+			skippedPlots: 0,
+		},
+		{
+			name:         "NoFlyZones_Zone_Cuts_The_Estate_Apart",
+			width:        5,
+			length:       3,
+			zones:        []Area{{From: Plot{X: 3, Y: 0}, To: Plot{X: 3, Y: 9}}},
+			distance:     52,
+			skippedPlots: 9,
+			unreachable:  []Area{{From: Plot{X: 4, Y: 1}, To: Plot{X: 5, Y: 3}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := New(Options{Width: tc.width, Length: tc.length, NoFlyZones: tc.zones})
+			assert.True(t, plan.Reachable())
+			assert.Equal(t, tc.distance, plan.Distance())
+			assert.Equal(t, tc.skippedPlots, plan.SkippedPlots())
+			assert.Equal(t, tc.unreachable, plan.Unreachable())
+		})
+	}
+}
+
+func TestNoFlyZonesCoverTheEstate(t *testing.T) {
+	plan := New(Options{Width: 3, Length: 2, NoFlyZones: []Area{{From: Plot{X: 3, Y: 2}, To: Plot{X: 1, Y: 1}}}})
+	assert.False(t, plan.Reachable())
+	assert.Equal(t, 6, plan.SkippedPlots())
+	assert.Equal(t, []Area{{From: Plot{X: 1, Y: 1}, To: Plot{X: 3, Y: 2}}}, plan.NoFlyZones())
+}
+
+func TestNoFlyZonesPath(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		width := random.Intn(10) + 1
+		length := random.Intn(10) + 1
+		zones := make([]Area, random.Intn(4))
+		for j := range zones {
+			zones[j] = Area{
+				From: Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+				To:   Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+			}
+		}
+		trees := randomTrees(random, width, length, random.Intn(width*length+1))
+
+		plan := New(Options{Width: width, Length: length, Trees: trees, NoFlyZones: zones})
+		closed := func(plot Plot) bool {
+			for _, zone := range plan.NoFlyZones() {
+				if zone.contains(plot) {
+					return true
+				}
+			}
+			return false
+		}
+
+		// Every free plot is either surveyed once or unreachable.
+		surveyed := make(map[Plot]bool)
+		for _, s := range plan.path.segments {
+			for offset := 0; s.surveys() && offset < s.len(); offset++ {
+				plot := s.plot(offset)
+				assert.False(t, surveyed[plot])
+				surveyed[plot] = true
+			}
+		}
+		unreachable := 0
+		for _, area := range plan.Unreachable() {
+			unreachable += area.width() * area.length()
+		}
+		assert.Equal(t, width*length-len(surveyed), plan.SkippedPlots())
+		for x := 1; x <= width; x++ {
+			for y := 1; y <= length; y++ {
+				if !closed(Plot{X: x, Y: y}) && !surveyed[Plot{X: x, Y: y}] {
+					unreachable--
+				}
+			}
+		}
+		assert.Equal(t, 0, unreachable)
+
+		if !plan.Reachable() {
+			continue
+		}
+
+		// The drone moves one plot at a time, never over a zone, and
+		// every ferry flight is as short as it can be.
+		for position := 0; position < plan.path.len(); position++ {
+			plot := plan.path.plot(position)
+			assert.False(t, closed(plot))
+			if position > 0 {
+				previous := plan.path.plot(position - 1)
+				assert.Equal(t, 1, abs(plot.X-previous.X)+abs(plot.Y-previous.Y))
+			}
+		}
+		for j := 1; j < len(plan.path.segments); j++ {
+			if _, ok := plan.path.segments[j].(block); !ok {
+				continue
+			}
+			k := j - 1
+			for !plan.path.segments[k].surveys() {
+				k--
+			}
+			start := plan.path.starts[j]
+			from := plan.path.starts[k] + plan.path.segments[k].len() - 1
+			assert.Equal(t, shortestFlight(width, length, closed, plan.path.plot(from), plan.path.plot(start)), start-from)
+		}
+
+		// The distance matches the drone flown over the path plot by plot.
+		altitudes := make(map[Plot]int)
+		for _, tree := range trees {
+			altitudes[Plot{X: tree.X, Y: tree.Y}] = tree.Height + DefaultClearance
+		}
+		altitude := func(position int) int {
+			if a, ok := altitudes[plan.path.plot(position)]; ok {
+				return a
+			}
+			return DefaultClearance
+		}
+		distance := altitude(0) + altitude(plan.path.len()-1)
+		for position := 1; position < plan.path.len(); position++ {
+			distance += PlotSize + abs(altitude(position)-altitude(position-1))
+		}
+		assert.Equal(t, distance, plan.Distance())
+	}
+}
+
+// shortestFlight returns the fewest steps between two plots without
+// flying over a closed plot, searching plot by plot.
+func shortestFlight(width, length int, closed func(Plot) bool, from, to Plot) int {
+	steps := map[Plot]int{from: 0}
+	queue := []Plot{from}
+	for len(queue) > 0 {
+		plot := queue[0]
+		queue = queue[1:]
+		if plot == to {
+			return steps[plot]
+		}
+		for _, next := range []Plot{{X: plot.X - 1, Y: plot.Y}, {X: plot.X + 1, Y: plot.Y}, {X: plot.X, Y: plot.Y - 1}, {X: plot.X, Y: plot.Y + 1}} {
+			if next.X < 1 || next.X > width || next.Y < 1 || next.Y > length || closed(next) {
+				continue
+			}
+			if _, ok := steps[next]; !ok {
+				steps[next] = steps[plot] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return -1
+}
+
+func BenchmarkNewNoFlyZones(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	trees := randomTrees(random, 50000, 50000, 3000)
+	zones := make([]Area, 20)
+	for i := range zones {
+		from := Plot{X: random.Intn(49000) + 1, Y: random.Intn(49000) + 1}
+		zones[i] = Area{From: from, To: Plot{X: from.X + random.Intn(1000), Y: from.Y + random.Intn(1000)}}
+	}
+	opts := Options{Width: 50000, Length: 50000, Trees: trees, NoFlyZones: zones}
+
+	b.Run("Rows", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			New(opts)
+		}
+	})
+	b.Run("Auto", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Choose(opts)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package droneplan
+
+import "sort"
+
+// Plot is the position of a plot inside an estate. Both coordinates start
+// at 1.
+type Plot struct {
+	X int
+	Y int
+}
+
+// Area is a rectangle of plots. From and To are opposite corners and both
+// belong to the area.
+type Area struct {
+	From Plot
+	To   Plot
+}
+
+// normalize returns the area with From at its south-west corner and To at
+// its north-east corner.
+func (a Area) normalize() Area {
+	if a.From.X > a.To.X {
+		a.From.X, a.To.X = a.To.X, a.From.X
+	}
+	if a.From.Y > a.To.Y {
+		a.From.Y, a.To.Y = a.To.Y, a.From.Y
+	}
+	return a
+}
+
+// contains reports whether the plot lies inside the normalized area.
+func (a Area) contains(plot Plot) bool {
+	return plot.X >= a.From.X && plot.X <= a.To.X && plot.Y >= a.From.Y && plot.Y <= a.To.Y
+}
+
+// width and length return the size of the normalized area in plots.
+func (a Area) width() int  { return a.To.X - a.From.X + 1 }
+func (a Area) length() int { return a.To.Y - a.From.Y + 1 }
+
+// segment is a stretch of the path flown in one go. Consecutive plots of a
+// segment are neighbours, and so are the last plot of a segment and the
+// first plot of the next one.
+type segment interface {
+	// len returns the number of plots of the segment.
+	len() int
+	// plot returns the plot at the given offset from the start of the
+	// segment.
+	plot(offset int) Plot
+	// offset returns the offset of the plot in the segment, and false when
+	// the segment does not cross the plot.
+	offset(plot Plot) (int, bool)
+	// turns returns the offsets where the drone may change direction.
+	turns() []int
+	// surveys reports whether the drone surveys the plots it crosses, or
+	// only ferries across them.
+	surveys() bool
+	// bounds returns the smallest area holding the plots of the segment.
+	bounds() Area
+}
+
+// block is a rectangle of plots surveyed row by row. The drone starts from
+// a corner, flies along the first row, moves to the next row and flies it
+// the other way, and keeps zigzagging until it has flown the last row.
+type block struct {
+	area  Area
+	start Plot
+}
+
+func (b block) len() int {
+	return b.area.width() * b.area.length()
+}
+
+// row and column turn a distance from the start corner, in rows and in
+// plots along a row, into a plot.
+func (b block) row(row int) int {
+	if b.start.Y == b.area.To.Y {
+		return b.area.To.Y - row
+	}
+	return b.area.From.Y + row
+}
+
+func (b block) column(column int) int {
+	if b.start.X == b.area.To.X {
+		return b.area.To.X - column
+	}
+	return b.area.From.X + column
+}
+
+func (b block) plot(offset int) Plot {
+	width := b.area.width()
+	row := offset / width
+	column := offset % width
+	if row%2 == 1 {
+		column = width - column - 1
+	}
+	return Plot{X: b.column(column), Y: b.row(row)}
+}
+
+func (b block) offset(plot Plot) (int, bool) {
+	if !b.area.contains(plot) {
+		return 0, false
+	}
+
+	width := b.area.width()
+	row := abs(plot.Y - b.start.Y)
+	column := abs(plot.X - b.start.X)
+	if row%2 == 1 {
+		column = width - column - 1
+	}
+	return row*width + column, true
+}
+
+func (b block) turns() []int {
+	width := b.area.width()
+	turns := make([]int, 0, 2*b.area.length())
+	for row := 0; row < b.area.length(); row++ {
+		turns = append(turns, row*width, row*width+width-1)
+	}
+	return turns
+}
+
+func (b block) surveys() bool {
+	return true
+}
+
+func (b block) bounds() Area {
+	return b.area
+}
+
+// line is a straight ferry flight across n plots, one step at a time.
+type line struct {
+	from Plot
+	step Plot
+	n    int
+}
+
+func (l line) len() int {
+	return l.n
+}
+
+func (l line) plot(offset int) Plot {
+	return Plot{X: l.from.X + offset*l.step.X, Y: l.from.Y + offset*l.step.Y}
+}
+
+func (l line) offset(plot Plot) (int, bool) {
+	dx, dy := plot.X-l.from.X, plot.Y-l.from.Y
+	offset := dx*l.step.X + dy*l.step.Y
+	if offset < 0 || offset >= l.n || l.plot(offset) != plot {
+		return 0, false
+	}
+	return offset, true
+}
+
+func (l line) turns() []int {
+	return []int{0, l.n - 1}
+}
+
+func (l line) surveys() bool {
+	return false
+}
+
+func (l line) bounds() Area {
+	return Area{From: l.from, To: l.plot(l.n - 1)}.normalize()
+}
+
+// path is the order the drone flies over the plots of an estate, made of
+// segments flown one after the other. Plots are numbered by their 0-based
+// position on the path. A plot is surveyed at most once, but ferry flights
+// may cross it again.
+type path struct {
+	segments []segment
+	starts   []int
+	size     int
+}
+
+func newPath(segments []segment) path {
+	p := path{segments: segments, starts: make([]int, len(segments))}
+	for i, s := range segments {
+		p.starts[i] = p.size
+		p.size += s.len()
+	}
+	return p
+}
+
+// len returns the number of plots on the path.
+func (p path) len() int {
+	return p.size
+}
+
+// locate returns the segment holding the given position and the offset of
+// the position inside it.
+func (p path) locate(position int) (int, int) {
+	i := sort.Search(len(p.starts), func(i int) bool {
+		return p.starts[i] > position
+	}) - 1
+	return i, position - p.starts[i]
+}
+
+// plot returns the plot at the given position on the path.
+func (p path) plot(position int) Plot {
+	i, offset := p.locate(position)
+	return p.segments[i].plot(offset)
+}
+
+// positions returns every position of the plot on the path, in path order.
+func (p path) positions(plot Plot) []int {
+	var positions []int
+	for i, s := range p.segments {
+		if offset, ok := s.offset(plot); ok {
+			positions = append(positions, p.starts[i]+offset)
+		}
+	}
+	return positions
+}
+
+// surveys reports whether the drone surveys the plot.
+func (p path) surveys(plot Plot) bool {
+	for _, s := range p.segments {
+		if _, ok := s.offset(plot); ok && s.surveys() {
+			return true
+		}
+	}
+	return false
+}
+
+// turns returns the positions where the drone may change direction.
+func (p path) turns() []int {
+	var turns []int
+	for i, s := range p.segments {
+		for _, offset := range s.turns() {
+			turns = append(turns, p.starts[i]+offset)
+		}
+	}
+	return turns
+}
@@ -0,0 +1,290 @@
+package droneplan
+
+import "sort"
+
+// Pattern is the order the drone surveys the plots of a block in.
+type Pattern string
+
+const (
+	// PatternRows flies row by row from the south-west corner, zigzagging
+	// between the west and east edges.
+	PatternRows Pattern = "rows"
+	// PatternRowsFromSouthEast, PatternRowsFromNorthWest and
+	// PatternRowsFromNorthEast fly row by row from the other corners.
+	PatternRowsFromSouthEast Pattern = "rows-se"
+	PatternRowsFromNorthWest Pattern = "rows-nw"
+	PatternRowsFromNorthEast Pattern = "rows-ne"
+	// PatternColumns flies column by column from the south-west corner,
+	// zigzagging between the south and north edges.
+	PatternColumns Pattern = "columns"
+	// PatternSpiral circles every block from its edges inwards.
+	PatternSpiral Pattern = "spiral"
+)
+
+// Patterns are every pattern a plan may follow, the default first.
+var Patterns = []Pattern{
+	PatternRows,
+	PatternRowsFromSouthEast,
+	PatternRowsFromNorthWest,
+	PatternRowsFromNorthEast,
+	PatternColumns,
+	PatternSpiral,
+}
+
+// Valid reports whether the pattern is one of Patterns.
+func (pattern Pattern) Valid() bool {
+	for _, p := range Patterns {
+		if p == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Candidate is the distance the plan of an estate flies with one pattern.
+type Candidate struct {
+	Pattern  Pattern
+	Distance int
+}
+
+// Choose builds the plan for an estate with every pattern and returns the
+// shortest, together with the distance of every candidate in the order of
+// Patterns. A tie goes to the pattern that comes first. The pattern in opts
+// is ignored.
+func Choose(opts Options) (*Plan, []Candidate) {
+	var best *Plan
+	candidates := make([]Candidate, 0, len(Patterns))
+	for _, pattern := range Patterns {
+		opts.Pattern = pattern
+		plan := New(opts)
+		if !plan.Reachable() {
+			return plan, nil
+		}
+
+		candidates = append(candidates, Candidate{Pattern: pattern, Distance: plan.Distance()})
+		if best == nil || plan.Distance() < best.Distance() {
+			best = plan
+		}
+	}
+	return best, candidates
+}
+
+// frame is the estate turned or mirrored so that a pattern starts from its
+// south-west corner and runs along its rows. Plans are laid out in the
+// frame and mapped back onto the estate.
+type frame struct {
+	width     int
+	length    int
+	flipX     bool
+	flipY     bool
+	transpose bool
+}
+
+func newFrame(pattern Pattern, width, length int) frame {
+	f := frame{width: width, length: length}
+	switch pattern {
+	case PatternRowsFromSouthEast:
+		f.flipX = true
+	case PatternRowsFromNorthWest:
+		f.flipY = true
+	case PatternRowsFromNorthEast:
+		f.flipX, f.flipY = true, true
+	case PatternColumns:
+		f.transpose = true
+	}
+	return f
+}
+
+// identity reports whether the frame is the estate itself.
+func (f frame) identity() bool {
+	return !f.flipX && !f.flipY && !f.transpose
+}
+
+// estate returns the estate as laid out in the frame.
+func (f frame) estate() Area {
+	if f.transpose {
+		return Area{From: Plot{X: 1, Y: 1}, To: Plot{X: f.length, Y: f.width}}
+	}
+	return Area{From: Plot{X: 1, Y: 1}, To: Plot{X: f.width, Y: f.length}}
+}
+
+// toFrame maps a plot of the estate into the frame, and toEstate maps it
+// back.
+func (f frame) toFrame(plot Plot) Plot {
+	if f.flipX {
+		plot.X = f.width + 1 - plot.X
+	}
+	if f.flipY {
+		plot.Y = f.length + 1 - plot.Y
+	}
+	if f.transpose {
+		plot.X, plot.Y = plot.Y, plot.X
+	}
+	return plot
+}
+
+func (f frame) toEstate(plot Plot) Plot {
+	if f.transpose {
+		plot.X, plot.Y = plot.Y, plot.X
+	}
+	if f.flipX {
+		plot.X = f.width + 1 - plot.X
+	}
+	if f.flipY {
+		plot.Y = f.length + 1 - plot.Y
+	}
+	return plot
+}
+
+// areaToFrame and areaToEstate map a normalized area between the estate
+// and the frame.
+func (f frame) areaToFrame(area Area) Area {
+	return Area{From: f.toFrame(area.From), To: f.toFrame(area.To)}.normalize()
+}
+
+func (f frame) areaToEstate(area Area) Area {
+	return Area{From: f.toEstate(area.From), To: f.toEstate(area.To)}.normalize()
+}
+
+// framed is a segment laid out in a frame, seen from the estate.
+type framed struct {
+	segment
+	frame frame
+}
+
+func (s framed) plot(offset int) Plot {
+	return s.frame.toEstate(s.segment.plot(offset))
+}
+
+func (s framed) offset(plot Plot) (int, bool) {
+	return s.segment.offset(s.frame.toFrame(plot))
+}
+
+func (s framed) bounds() Area {
+	return s.frame.areaToEstate(s.segment.bounds())
+}
+
+// spiral is a rectangle of plots surveyed in rings from its edges inwards.
+// The drone starts from a corner, flies along the first row, up the far
+// column, back along the last row and down the near column, then moves one
+// plot in and circles the next ring, until it reaches the middle.
+//
+// Plots are addressed in ring coordinates: u along the rows and v along the
+// columns, both counted from the start corner.
+type spiral struct {
+	area  Area
+	start Plot
+}
+
+func (s spiral) len() int {
+	return s.area.width() * s.area.length()
+}
+
+// before returns the number of plots in the rings outside ring k.
+func (s spiral) before(k int) int {
+	if k >= s.rings() {
+		return s.len()
+	}
+	w, l := s.area.width(), s.area.length()
+	return w*l - (w-2*k)*(l-2*k)
+}
+
+// rings returns the number of rings.
+func (s spiral) rings() int {
+	return (min(s.area.width(), s.area.length()) + 1) / 2
+}
+
+// ring returns the width and length of ring k.
+func (s spiral) ring(k int) (int, int) {
+	return s.area.width() - 2*k, s.area.length() - 2*k
+}
+
+func (s spiral) plot(offset int) Plot {
+	k := sort.Search(s.rings(), func(k int) bool {
+		return s.before(k+1) > offset
+	})
+	rw, rl := s.ring(k)
+	i := offset - s.before(k)
+
+	var u, v int
+	switch {
+	case rl == 1:
+		u, v = k+i, k
+	case rw == 1:
+		u, v = k, k+i
+	case i < rw:
+		u, v = k+i, k
+	case i < rw+rl-1:
+		u, v = k+rw-1, k+i-rw+1
+	case i < 2*rw+rl-2:
+		u, v = k+rw-1-(i-rw-rl+2), k+rl-1
+	default:
+		u, v = k, k+rl-1-(i-2*rw-rl+3)
+	}
+	return s.toPlot(u, v)
+}
+
+func (s spiral) offset(plot Plot) (int, bool) {
+	if !s.area.contains(plot) {
+		return 0, false
+	}
+
+	u, v := abs(plot.X-s.start.X), abs(plot.Y-s.start.Y)
+	w, l := s.area.width(), s.area.length()
+	k := min(min(u, v), min(w-1-u, l-1-v))
+	rw, rl := s.ring(k)
+
+	var i int
+	switch {
+	case rl == 1:
+		i = u - k
+	case rw == 1:
+		i = v - k
+	case v == k:
+		i = u - k
+	case u == k+rw-1:
+		i = rw - 1 + v - k
+	case v == k+rl-1:
+		i = rw + rl - 2 + k + rw - 1 - u
+	default:
+		i = 2*rw + rl - 3 + k + rl - 1 - v
+	}
+	return s.before(k) + i, true
+}
+
+// toPlot turns ring coordinates into a plot.
+func (s spiral) toPlot(u, v int) Plot {
+	plot := Plot{X: s.area.From.X + u, Y: s.area.From.Y + v}
+	if s.start.X == s.area.To.X {
+		plot.X = s.area.To.X - u
+	}
+	if s.start.Y == s.area.To.Y {
+		plot.Y = s.area.To.Y - v
+	}
+	return plot
+}
+
+func (s spiral) turns() []int {
+	turns := make([]int, 0, 4*s.rings()+1)
+	for k := 0; k < s.rings(); k++ {
+		rw, rl := s.ring(k)
+		size := 2*rw + 2*rl - 4
+		if rw == 1 || rl == 1 {
+			size = rw * rl
+		}
+		for _, i := range []int{0, rw - 1, rw + rl - 2, 2*rw + rl - 3, size - 1} {
+			if i >= 0 && i < size {
+				turns = append(turns, s.before(k)+i)
+			}
+		}
+	}
+	return turns
+}
+
+func (s spiral) surveys() bool {
+	return true
+}
+
+func (s spiral) bounds() Area {
+	return s.area
+}
@@ -0,0 +1,158 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatterns(t *testing.T) {
+	testCases := []struct {
+		pattern Pattern
+		width   int
+		length  int
+		plots   []Plot
+	}{
+		{
+			pattern: PatternRows,
+			width:   3,
+			length:  2,
+			plots:   []Plot{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 2}, {X: 2, Y: 2}, {X: 1, Y: 2}},
+		},
+		{
+			pattern: PatternRowsFromSouthEast,
+			width:   3,
+			length:  2,
+			plots:   []Plot{{X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}},
+		},
+		{
+			pattern: PatternRowsFromNorthWest,
+			width:   3,
+			length:  2,
+			plots:   []Plot{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}},
+		},
+		{
+			pattern: PatternRowsFromNorthEast,
+			width:   3,
+			length:  2,
+			plots:   []Plot{{X: 3, Y: 2}, {X: 2, Y: 2}, {X: 1, Y: 2}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}},
+		},
+		{
+			pattern: PatternColumns,
+			width:   3,
+			length:  2,
+			plots:   []Plot{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 2}},
+		},
+		{
+			pattern: PatternSpiral,
+			width:   4,
+			length:  3,
+			plots: []Plot{
+				{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1},
+				{X: 4, Y: 2}, {X: 4, Y: 3}, {X: 3, Y: 3}, {X: 2, Y: 3},
+				{X: 1, Y: 3}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.pattern), func(t *testing.T) {
+			plan := New(Options{Width: tc.width, Length: tc.length, Pattern: tc.pattern})
+			assert.Equal(t, tc.pattern, plan.Pattern())
+
+			plots := make([]Plot, 0, plan.path.len())
+			for position := 0; position < plan.path.len(); position++ {
+				plots = append(plots, plan.path.plot(position))
+			}
+			assert.Equal(t, tc.plots, plots)
+		})
+	}
+}
+
+func TestChoose(t *testing.T) {
+	// A column of tall trees down the middle: flying the columns crosses
+	// them once, flying the rows crosses them on every row, and the spiral
+	// crosses them on four rows and lands among them in the middle.
+	var trees []Tree
+	for y := 1; y <= 5; y++ {
+		trees = append(trees, Tree{X: 3, Y: y, Height: 30})
+	}
+
+	plan, candidates := Choose(Options{Width: 5, Length: 5, Trees: trees, Pattern: PatternSpiral})
+	assert.Equal(t, PatternColumns, plan.Pattern())
+	assert.Equal(t, []Candidate{
+		{Pattern: PatternRows, Distance: 542},
+		{Pattern: PatternRowsFromSouthEast, Distance: 542},
+		{Pattern: PatternRowsFromNorthWest, Distance: 542},
+		{Pattern: PatternRowsFromNorthEast, Distance: 542},
+		{Pattern: PatternColumns, Distance: 302},
+		{Pattern: PatternSpiral, Distance: 542},
+	}, candidates)
+	assert.Equal(t, 302, plan.Distance())
+}
+
+func TestPatternsPath(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 600; i++ {
+		width := random.Intn(10) + 1
+		length := random.Intn(10) + 1
+		zones := make([]Area, random.Intn(3))
+		for j := range zones {
+			zones[j] = Area{
+				From: Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+				To:   Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+			}
+		}
+		trees := randomTrees(random, width, length, random.Intn(width*length+1))
+		opts := Options{Width: width, Length: length, Trees: trees, NoFlyZones: zones, Pattern: Patterns[i%len(Patterns)]}
+		if i%4 == 3 {
+			opts.Region = &Area{
+				From: Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+				To:   Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+			}
+		}
+
+		plan := New(opts)
+		if !plan.Reachable() {
+			continue
+		}
+
+		// Every plot is found back at its position, and the drone moves
+		// one plot at a time over a plot it surveys at most once.
+		surveyed := make(map[Plot]bool)
+		for j, s := range plan.path.segments {
+			for offset := 0; offset < s.len(); offset++ {
+				plot := s.plot(offset)
+				found, ok := s.offset(plot)
+				assert.True(t, ok)
+				assert.Equal(t, offset, found)
+				assert.True(t, s.bounds().contains(plot))
+				if s.surveys() {
+					assert.False(t, surveyed[plot])
+					surveyed[plot] = true
+				}
+				if position := plan.path.starts[j] + offset; position > 0 {
+					previous := plan.path.plot(position - 1)
+					assert.Equal(t, 1, abs(plot.X-previous.X)+abs(plot.Y-previous.Y))
+				}
+			}
+		}
+		assert.Equal(t, plan.size-len(surveyed), plan.SkippedPlots())
+
+		// The distance matches the drone flown over the path plot by plot.
+		heights := make(map[Plot]int)
+		for _, tree := range trees {
+			heights[Plot{X: tree.X, Y: tree.Y}] = tree.Height
+		}
+		altitude := func(position int) int {
+			return heights[plan.path.plot(position)] + DefaultClearance
+		}
+		distance := altitude(0) + altitude(plan.path.len()-1)
+		for position := 1; position < plan.path.len(); position++ {
+			distance += PlotSize + abs(altitude(position)-altitude(position-1))
+		}
+		assert.Equal(t, distance, plan.Distance())
+	}
+}
@@ -0,0 +1,616 @@
+// Package droneplan works out the flight a drone makes to survey an estate.
+//
+// An estate is a grid of square plots. The drone takes off from the first
+// plot, cruises a fixed clearance above every plot, climbing over the trees
+// and obstacles and descending after them, and lands on the last plot. Only
+// the plots holding a tree or an obstacle change the altitude, so a plan is
+// built from them alone and the empty stretches between them are worked out
+// in closed form. Building a plan costs O(t log t) for t trees and
+// obstacles, whatever the estate size.
+//
+// Altitudes are absolute, on the datum of the ground elevation. The ground
+// lies at the base elevation of the estate except on the plots given an
+// elevation of their own; the drone follows the ground over them, so every
+// such plot counts as one more stop of the plan.
+//
+// The drone never flies over a no-fly zone. It surveys the plots around the
+// zones block by block and ferries between the blocks along the shortest
+// flight that keeps clear of the zones, crossing plots it may have
+// surveyed already.
+package droneplan
+
+import "sort"
+
+const (
+	// PlotSize is the length in metres of one side of an estate plot.
+	PlotSize = 10
+	// DefaultClearance is the height in metres the drone keeps above a
+	// plot unless told otherwise.
+	DefaultClearance = 1
+)
+
+// Tree is a tree growing on a plot of the estate.
+type Tree struct {
+	X      int
+	Y      int
+	Height int
+	// Inspect flags the tree for close inspection photos, see Inspect.
+	Inspect bool
+}
+
+// Obstacle is a building or structure standing on a plot of the estate,
+// such as a water tower or a mill. The drone climbs over it like over a
+// tree.
+type Obstacle struct {
+	X      int
+	Y      int
+	Height int
+}
+
+// Elevation is the ground elevation in metres of a plot of the estate.
+type Elevation struct {
+	X         int
+	Y         int
+	Elevation int
+}
+
+// Waypoint is a point on the drone path together with the distance the
+// drone has flown when it gets there.
+type Waypoint struct {
+	X        int
+	Y        int
+	Altitude int
+	Distance int
+}
+
+// stop is a plot on the path that holds a tree or an obstacle or lies off
+// the base elevation, together with its ground elevation and the vertical
+// distance the drone has flown when it is above that plot.
+type stop struct {
+	position int
+	ground   int
+	altitude int
+	vertical int
+}
+
+// Options describes the estate to survey and how the drone flies over it.
+type Options struct {
+	// Width and Length are the size of the estate in plots.
+	Width  int
+	Length int
+	// Trees are the trees growing on the estate. Trees outside the estate
+	// are ignored.
+	Trees []Tree
+	// Obstacles are the obstacles standing on the estate. Obstacles
+	// outside the estate are ignored.
+	Obstacles []Obstacle
+	// Clearance is the height in metres the drone keeps above every plot.
+	// It defaults to DefaultClearance.
+	Clearance int
+	// BaseElevation is the ground elevation in metres of every plot
+	// without an elevation of its own.
+	BaseElevation int
+	// Elevations are the plots whose ground lies off the base elevation.
+	// Elevations outside the estate are ignored, and the last elevation
+	// given to a plot wins.
+	Elevations []Elevation
+	// NoFlyZones are the areas of the estate the drone must not fly over.
+	// The parts of a zone outside the estate are ignored.
+	NoFlyZones []Area
+	// Region limits the survey to an area of the estate. The drone takes
+	// off from plot (1, 1), ferries to the region and back, and lands
+	// there again. The parts of the region outside the estate are ignored.
+	// A nil region surveys the whole estate.
+	Region *Area
+	// Pattern is the order the drone surveys the plots in. It defaults to
+	// PatternRows.
+	Pattern Pattern
+	// MaxAltitude is the ceiling in metres above the ground the drone must
+	// not fly over, or 0 for none. A tree or an obstacle taller than the
+	// ceiling less the clearance forces the drone above it.
+	MaxAltitude int
+	// SkipAboveCeiling leaves the plots that force the drone above the
+	// ceiling out of the survey, and keeps the drone clear of them like of
+	// a no-fly zone.
+	SkipAboveCeiling bool
+}
+
+// Plan is the survey flight over one estate.
+type Plan struct {
+	path      path
+	clearance int
+	base      int
+	stops     []stop
+	// elevations holds the ground elevation of the plots off the base.
+	elevations map[Plot]int
+	// occupied holds the altitude the drone cruises at above every plot on
+	// the path holding a tree or an obstacle.
+	occupied map[Plot]int
+	// trees and obstacles are everything standing on the estate, kept to
+	// draw it.
+	trees     []Tree
+	obstacles []Obstacle
+
+	pattern Pattern
+	estate  Area
+	// aboveCeiling holds the plots that force the drone above its ceiling,
+	// and skipCeiling whether the drone keeps clear of them.
+	aboveCeiling []Plot
+	skipCeiling  bool
+	region       Area
+	size         int
+	surveyed     int
+	noFlyZones   []Area
+	unreachable  []Area
+}
+
+// New builds the plan for an estate.
+func New(opts Options) *Plan {
+	p := &Plan{
+		clearance:  opts.Clearance,
+		base:       opts.BaseElevation,
+		elevations: make(map[Plot]int),
+		occupied:   make(map[Plot]int),
+		trees:      opts.Trees,
+		obstacles:  opts.Obstacles,
+	}
+	if p.clearance <= 0 {
+		p.clearance = DefaultClearance
+	}
+
+	estate := Area{From: Plot{X: 1, Y: 1}, To: Plot{X: opts.Width, Y: opts.Length}}
+	p.estate = estate
+	for _, zone := range opts.NoFlyZones {
+		if zone, ok := clip(zone.normalize(), estate); ok {
+			p.noFlyZones = append(p.noFlyZones, zone)
+		}
+	}
+
+	if opts.MaxAltitude > 0 {
+		p.aboveCeiling = aboveCeiling(opts, estate, p.clearance)
+		p.skipCeiling = opts.SkipAboveCeiling
+	}
+
+	p.pattern = opts.Pattern
+	if !p.pattern.Valid() {
+		p.pattern = PatternRows
+	}
+	segments, unreachable := p.layout(estate, opts.Region)
+	p.path = newPath(segments)
+	p.unreachable = unreachable
+	for _, s := range segments {
+		if s.surveys() {
+			p.surveyed += s.len()
+		}
+	}
+
+	altitudes := make(map[int]int, len(opts.Trees)+len(opts.Obstacles)+len(opts.Elevations))
+	for _, elevation := range opts.Elevations {
+		plot := Plot{X: elevation.X, Y: elevation.Y}
+		switch {
+		case !estate.contains(plot):
+		case elevation.Elevation == p.base:
+			delete(p.elevations, plot)
+		default:
+			p.elevations[plot] = elevation.Elevation
+		}
+	}
+	for plot, elevation := range p.elevations {
+		for _, position := range p.path.positions(plot) {
+			altitudes[position] = elevation + p.clearance
+		}
+	}
+	occupy := func(plot Plot, height int) {
+		altitude := p.groundOf(plot) + height + p.clearance
+		for _, position := range p.path.positions(plot) {
+			altitudes[position] = altitude
+			p.occupied[plot] = altitude
+		}
+	}
+	for _, tree := range opts.Trees {
+		occupy(Plot{X: tree.X, Y: tree.Y}, tree.Height)
+	}
+	for _, obstacle := range opts.Obstacles {
+		occupy(Plot{X: obstacle.X, Y: obstacle.Y}, obstacle.Height)
+	}
+
+	p.stops = make([]stop, 0, len(altitudes))
+	for position, altitude := range altitudes {
+		p.stops = append(p.stops, stop{
+			position: position,
+			ground:   p.groundOf(p.path.plot(position)),
+			altitude: altitude,
+		})
+	}
+	sort.Slice(p.stops, func(i, j int) bool {
+		return p.stops[i].position < p.stops[j].position
+	})
+
+	// The drone waits on the ground just before the first plot.
+	previous := stop{position: -1}
+	if p.path.len() > 0 {
+		previous.altitude = p.groundOf(p.path.plot(0))
+	}
+	vertical := 0
+	for i := range p.stops {
+		vertical += p.verticalBetween(previous, p.stops[i])
+		p.stops[i].vertical = vertical
+		previous = p.stops[i]
+	}
+
+	return p
+}
+
+// CountAboveCeiling returns the number of plots of the estate holding a
+// tree or an obstacle that forces the drone above its ceiling. Every such
+// plot skipped is one more area the drone keeps clear of.
+func CountAboveCeiling(opts Options) int {
+	if opts.MaxAltitude <= 0 {
+		return 0
+	}
+
+	clearance := opts.Clearance
+	if clearance <= 0 {
+		clearance = DefaultClearance
+	}
+	estate := Area{From: Plot{X: 1, Y: 1}, To: Plot{X: opts.Width, Y: opts.Length}}
+	return len(aboveCeiling(opts, estate, clearance))
+}
+
+// aboveCeiling returns the plots of the estate holding a tree or an
+// obstacle that forces the drone above the ceiling of the options, in the
+// order they are given.
+func aboveCeiling(opts Options, estate Area, clearance int) []Plot {
+	var plots []Plot
+	seen := make(map[Plot]bool)
+	above := func(plot Plot, height int) {
+		if height+clearance > opts.MaxAltitude && estate.contains(plot) && !seen[plot] {
+			seen[plot] = true
+			plots = append(plots, plot)
+		}
+	}
+	for _, tree := range opts.Trees {
+		above(Plot{X: tree.X, Y: tree.Y}, tree.Height)
+	}
+	for _, obstacle := range opts.Obstacles {
+		above(Plot{X: obstacle.X, Y: obstacle.Y}, obstacle.Height)
+	}
+	return plots
+}
+
+// layout lays the path of the plan out over the estate, or over the region
+// of it when one is given, in the frame of the plan pattern.
+func (p *Plan) layout(estate Area, region *Area) (segments []segment, unreachable []Area) {
+	p.region = estate
+	p.size = estate.width() * estate.length()
+	if region != nil {
+		clipped, ok := clip(region.normalize(), estate)
+		if !ok {
+			p.size = 0
+			return nil, nil
+		}
+		p.region = clipped
+		p.size = clipped.width() * clipped.length()
+		region = &clipped
+	}
+
+	f := newFrame(p.pattern, estate.To.X, estate.To.Y)
+	zones := make([]Area, 0, len(p.noFlyZones)+len(p.aboveCeiling))
+	for _, zone := range p.noFlyZones {
+		zones = append(zones, f.areaToFrame(zone))
+	}
+	if p.skipCeiling {
+		for _, plot := range p.aboveCeiling {
+			zones = append(zones, f.areaToFrame(Area{From: plot, To: plot}))
+		}
+	}
+	if region != nil {
+		inFrame := f.areaToFrame(*region)
+		region = &inFrame
+	}
+	newBlock := func(area Area, start Plot) segment {
+		return block{area: area, start: start}
+	}
+	if p.pattern == PatternSpiral {
+		newBlock = func(area Area, start Plot) segment {
+			return spiral{area: area, start: start}
+		}
+	}
+
+	segments, unreachable = layout(f.estate(), zones, region, f.toFrame(estate.From), newBlock)
+	if f.identity() {
+		return segments, unreachable
+	}
+	for i, s := range segments {
+		segments[i] = framed{segment: s, frame: f}
+	}
+	for i, area := range unreachable {
+		unreachable[i] = f.areaToEstate(area)
+	}
+	return segments, unreachable
+}
+
+// Pattern returns the order the drone surveys the plots in.
+func (p *Plan) Pattern() Pattern {
+	return p.pattern
+}
+
+// Reachable reports whether the drone has any plot to survey. A plan
+// without one has no path, and none of its flights may be worked out.
+func (p *Plan) Reachable() bool {
+	return p.path.len() > 0
+}
+
+// SkippedPlots returns the number of plots of the estate, or of the region,
+// the drone does not survey, because they lie in a no-fly zone, force the
+// drone above its ceiling when those are skipped, or cannot be reached
+// without crossing one of them.
+func (p *Plan) SkippedPlots() int {
+	return p.size - p.surveyed
+}
+
+// NoFlyZones returns the no-fly zones inside the estate, or the parts of
+// them inside the region.
+func (p *Plan) NoFlyZones() []Area {
+	var zones []Area
+	for _, zone := range p.noFlyZones {
+		if zone, ok := clip(zone, p.region); ok {
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
+// AboveCeiling returns the plots the drone flies over holding a tree or an
+// obstacle that forces it above its ceiling. It is empty when those plots
+// are skipped.
+func (p *Plan) AboveCeiling() []Plot {
+	if p.skipCeiling {
+		return nil
+	}
+
+	var above []Plot
+	for _, plot := range p.aboveCeiling {
+		if _, ok := p.occupied[plot]; ok {
+			above = append(above, plot)
+		}
+	}
+	return above
+}
+
+// SkippedAboveCeiling returns the plots of the estate, or of the region,
+// left out of the survey because they force the drone above its ceiling.
+// The plots inside a no-fly zone are left out for the zone.
+func (p *Plan) SkippedAboveCeiling() []Plot {
+	if !p.skipCeiling {
+		return nil
+	}
+
+	var skipped []Plot
+	for _, plot := range p.aboveCeiling {
+		if !p.region.contains(plot) {
+			continue
+		}
+		inZone := false
+		for _, zone := range p.noFlyZones {
+			if zone.contains(plot) {
+				inZone = true
+				break
+			}
+		}
+		if !inZone {
+			skipped = append(skipped, plot)
+		}
+	}
+	return skipped
+}
+
+// Bounds returns the smallest area holding every plot the drone flies
+// over, ferry flights included. It must not be called on a plan that is
+// not reachable.
+func (p *Plan) Bounds() Area {
+	bounds := p.path.segments[0].bounds()
+	for _, s := range p.path.segments[1:] {
+		area := s.bounds()
+		bounds.From.X = min(bounds.From.X, area.From.X)
+		bounds.From.Y = min(bounds.From.Y, area.From.Y)
+		bounds.To.X = max(bounds.To.X, area.To.X)
+		bounds.To.Y = max(bounds.To.Y, area.To.Y)
+	}
+	return bounds
+}
+
+// Unreachable returns the blocks of plots outside the no-fly zones the
+// drone cannot reach without crossing a zone.
+func (p *Plan) Unreachable() []Area {
+	return p.unreachable
+}
+
+// Distance returns the total distance in metres the drone flies: the
+// take-off, the horizontal legs between neighbouring plots, every climb and
+// descent needed to keep its clearance above the trees and obstacles, and
+// the landing.
+func (p *Plan) Distance() int {
+	return p.sectionDistance(0, p.path.len()-1)
+}
+
+// Rest follows the drone with a battery that lasts maxDistance metres. The
+// drone keeps enough battery to land, so it lands on the last plot it can
+// reach with that reserve. The returned waypoint is the landing point and
+// its distance includes the landing. A battery too weak to take off leaves
+// the drone on the first plot with no distance flown.
+func (p *Plan) Rest(maxDistance int) Waypoint {
+	position := p.lastLanding(0, p.path.len()-1, maxDistance)
+	if position < 0 {
+		plot := p.path.plot(0)
+		return Waypoint{X: plot.X, Y: plot.Y, Altitude: p.groundOf(plot)}
+	}
+
+	plot := p.path.plot(position)
+	return Waypoint{
+		X:        plot.X,
+		Y:        plot.Y,
+		Altitude: p.groundOf(plot),
+		Distance: p.distanceAt(position) + p.heightAt(position),
+	}
+}
+
+// CountWaypoints returns how many waypoints the drone path has: the
+// take-off point, one waypoint above every plot and the landing point.
+func (p *Plan) CountWaypoints() int {
+	return p.path.len() + 2
+}
+
+// Waypoints returns at most limit waypoints of the drone path, starting
+// from the waypoint at index offset.
+func (p *Plan) Waypoints(offset, limit int) []Waypoint {
+	end := offset + limit
+	if total := p.CountWaypoints(); end > total {
+		end = total
+	}
+
+	waypoints := []Waypoint{}
+	for index := offset; index < end; index++ {
+		waypoints = append(waypoints, p.waypoint(index))
+	}
+
+	return waypoints
+}
+
+// waypoint returns the waypoint at the given index of the drone path.
+func (p *Plan) waypoint(index int) Waypoint {
+	switch index {
+	case 0:
+		plot := p.path.plot(0)
+		return Waypoint{X: plot.X, Y: plot.Y, Altitude: p.groundOf(plot)}
+	case p.path.len() + 1:
+		plot := p.path.plot(p.path.len() - 1)
+		return Waypoint{X: plot.X, Y: plot.Y, Altitude: p.groundOf(plot), Distance: p.Distance()}
+	}
+
+	position := index - 1
+	plot := p.path.plot(position)
+	return Waypoint{
+		X:        plot.X,
+		Y:        plot.Y,
+		Altitude: p.altitudeAt(position),
+		Distance: p.distanceAt(position),
+	}
+}
+
+// distanceAt returns the distance the drone has flown when it is above the
+// plot at the given position, take-off included.
+func (p *Plan) distanceAt(position int) int {
+	return PlotSize*position + p.verticalAt(position)
+}
+
+// verticalAt returns the vertical distance the drone has flown when it is
+// above the plot at the given position.
+func (p *Plan) verticalAt(position int) int {
+	i := p.lastStopAt(position)
+	if i < 0 {
+		return p.clearance
+	}
+
+	last := p.stops[i]
+	if last.position == position {
+		return last.vertical
+	}
+	return last.vertical + abs(last.altitude-p.cruise())
+}
+
+// altitudeAt returns the altitude the drone cruises at above the plot at
+// the given position.
+func (p *Plan) altitudeAt(position int) int {
+	i := p.lastStopAt(position)
+	if i >= 0 && p.stops[i].position == position {
+		return p.stops[i].altitude
+	}
+	return p.cruise()
+}
+
+// heightAt returns the height above the ground the drone cruises at above
+// the plot at the given position. It is the height the drone climbs on
+// take-off from that plot and descends on landing there.
+func (p *Plan) heightAt(position int) int {
+	i := p.lastStopAt(position)
+	if i >= 0 && p.stops[i].position == position {
+		return p.stops[i].altitude - p.stops[i].ground
+	}
+	return p.clearance
+}
+
+// cruise returns the altitude the drone cruises at above an empty plot at
+// the base elevation.
+func (p *Plan) cruise() int {
+	return p.base + p.clearance
+}
+
+// groundOf returns the ground elevation of the plot.
+func (p *Plan) groundOf(plot Plot) int {
+	if elevation, ok := p.elevations[plot]; ok {
+		return elevation
+	}
+	return p.base
+}
+
+// lastLanding returns the last position from start to end the drone can
+// land on with no more than limit metres flown, counted like distanceAt,
+// or start-1 when there is none. The distance flown only grows along the
+// path but the landing does not: the plot after a tall tree or up a rise
+// may cost less to land on than the one before it. So the plots the drone
+// reaches are found with a search and the landing among them by going back
+// from the farthest, which only takes more than a step or two over stops.
+func (p *Plan) lastLanding(start, end, limit int) int {
+	reachable := start + sort.Search(end-start+1, func(i int) bool {
+		return p.distanceAt(start+i) > limit
+	})
+	for position := reachable - 1; position >= start; position-- {
+		if p.distanceAt(position)+p.heightAt(position) <= limit {
+			return position
+		}
+	}
+	return start - 1
+}
+
+// lastStopAt returns the index of the last stop at or before the given
+// position, or -1 when there is none.
+func (p *Plan) lastStopAt(position int) int {
+	return sort.Search(len(p.stops), func(i int) bool {
+		return p.stops[i].position > position
+	}) - 1
+}
+
+// verticalBetween returns the vertical distance flown from one stop to the
+// next. Any plot between them is empty and flown at the cruise altitude.
+func (p *Plan) verticalBetween(from, to stop) int {
+	if to.position == from.position+1 {
+		return abs(to.altitude - from.altitude)
+	}
+	return abs(from.altitude-p.cruise()) + abs(to.altitude-p.cruise())
+}
+
+// clip returns the part of the normalized area inside the estate, and false
+// when there is none.
+func clip(area, estate Area) (Area, bool) {
+	if area.From.X < estate.From.X {
+		area.From.X = estate.From.X
+	}
+	if area.From.Y < estate.From.Y {
+		area.From.Y = estate.From.Y
+	}
+	if area.To.X > estate.To.X {
+		area.To.X = estate.To.X
+	}
+	if area.To.Y > estate.To.Y {
+		area.To.Y = estate.To.Y
+	}
+	return area, area.From.X <= area.To.X && area.From.Y <= area.To.Y
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
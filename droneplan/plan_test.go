@@ -0,0 +1,264 @@
+package droneplan
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rowTrees = []Tree{
+	{X: 2, Y: 1, Height: 5},
+	{X: 3, Y: 1, Height: 3},
+	{X: 4, Y: 1, Height: 4},
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		name      string
+		width     int
+		length    int
+		trees     []Tree
+		obstacles []Obstacle
+		distance  int
+	}{
+		{
+			name:     "Distance_Single_Plot",
+			width:    1,
+			length:   1,
+			distance: 2,
+		},
+		{
+			name:     "Distance_Without_Trees",
+			width:    3,
+			length:   2,
+			distance: 52,
+		},
+		{
+			name:     "Distance_Climb_And_Descend_Between_Trees",
+			width:    5,
+			length:   1,
+			trees:    rowTrees,
+			distance: 54,
+		},
+		{
+			name:   "Distance_Take_Off_And_Land_On_Trees",
+			width:  2,
+			length: 2,
+			trees: []Tree{
+				{X: 1, Y: 1, Height: 10},
+				{X: 1, Y: 2, Height: 20},
+			},
+			distance: 92,
+		},
+		{
+			name:   "Distance_Ignores_Trees_Outside_The_Estate",
+			width:  3,
+			length: 2,
+			trees: []Tree{
+				{X: 4, Y: 1, Height: 10},
+				{X: 1, Y: 3, Height: 10},
+			},
+			distance: 52,
+		},
+		{
+			name:      "Distance_Climb_Over_Obstacles",
+			width:     3,
+			length:    1,
+			trees:     []Tree{{X: 3, Y: 1, Height: 5}},
+			obstacles: []Obstacle{{X: 2, Y: 1, Height: 40}},
+			distance:  102,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.distance, New(Options{Width: tc.width, Length: tc.length, Trees: tc.trees, Obstacles: tc.obstacles}).Distance())
+		})
+	}
+}
+
+func TestDistanceMatchesPlotByPlotFlight(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		width := random.Intn(8) + 1
+		length := random.Intn(8) + 1
+		trees := randomTrees(random, width, length, random.Intn(width*length+1))
+
+		plan := New(Options{Width: width, Length: length, Trees: trees})
+		waypoints := flyPlotByPlot(width, length, trees)
+
+		assert.Equal(t, waypoints[len(waypoints)-1].Distance, plan.Distance())
+		assert.Equal(t, waypoints, plan.Waypoints(0, len(waypoints)))
+	}
+}
+
+func TestRest(t *testing.T) {
+	plan := New(Options{Width: 5, Length: 1, Trees: rowTrees})
+
+	testCases := []struct {
+		name        string
+		maxDistance int
+		rest        Waypoint
+	}{
+		{
+			name:        "Rest_Battery_Runs_Out",
+			maxDistance: 40,
+			rest:        Waypoint{X: 3, Y: 1, Distance: 32},
+		},
+		{
+			name:        "Rest_Battery_Lasts_The_Whole_Plan",
+			maxDistance: 54,
+			rest:        Waypoint{X: 5, Y: 1, Distance: 54},
+		},
+		{
+			name:        "Rest_Battery_Too_Short_To_Take_Off",
+			maxDistance: 1,
+			rest:        Waypoint{X: 1, Y: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.rest, plan.Rest(tc.maxDistance))
+		})
+	}
+}
+
+func TestWaypoints(t *testing.T) {
+	plan := New(Options{Width: 2, Length: 2, Trees: []Tree{{X: 2, Y: 2, Height: 4}}})
+	path := []Waypoint{
+		{X: 1, Y: 1, Altitude: 0, Distance: 0},
+		{X: 1, Y: 1, Altitude: 1, Distance: 1},
+		{X: 2, Y: 1, Altitude: 1, Distance: 11},
+		{X: 2, Y: 2, Altitude: 5, Distance: 25},
+		{X: 1, Y: 2, Altitude: 1, Distance: 39},
+		{X: 1, Y: 2, Altitude: 0, Distance: 40},
+	}
+
+	testCases := []struct {
+		name      string
+		offset    int
+		limit     int
+		waypoints []Waypoint
+	}{
+		{
+			name:      "Waypoints_Whole_Path",
+			offset:    0,
+			limit:     10,
+			waypoints: path,
+		},
+		{
+			name:      "Waypoints_Middle_Page",
+			offset:    2,
+			limit:     2,
+			waypoints: path[2:4],
+		},
+		{
+			name:      "Waypoints_Landing_Only",
+			offset:    5,
+			limit:     2,
+			waypoints: path[5:],
+		},
+		{
+			name:      "Waypoints_Past_The_End",
+			offset:    6,
+			limit:     2,
+			waypoints: []Waypoint{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.waypoints, plan.Waypoints(tc.offset, tc.limit))
+		})
+	}
+	assert.Equal(t, len(path), plan.CountWaypoints())
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, count := range []int{1000, 5000} {
+		trees := randomTrees(rand.New(rand.NewSource(1)), 50000, 50000, count)
+
+		b.Run(fmt.Sprintf("Trees_%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				New(Options{Width: 50000, Length: 50000, Trees: trees})
+			}
+		})
+	}
+}
+
+func BenchmarkDistance(b *testing.B) {
+	trees := randomTrees(rand.New(rand.NewSource(1)), 50000, 50000, 5000)
+
+	for i := 0; i < b.N; i++ {
+		New(Options{Width: 50000, Length: 50000, Trees: trees}).Distance()
+	}
+}
+
+func BenchmarkRest(b *testing.B) {
+	plan := New(Options{Width: 50000, Length: 50000, Trees: randomTrees(rand.New(rand.NewSource(1)), 50000, 50000, 5000)})
+	maxDistance := plan.Distance() / 2
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plan.Rest(maxDistance)
+	}
+}
+
+func BenchmarkWaypoints(b *testing.B) {
+	plan := New(Options{Width: 50000, Length: 50000, Trees: randomTrees(rand.New(rand.NewSource(1)), 50000, 50000, 5000)})
+	offset := plan.CountWaypoints() / 2
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plan.Waypoints(offset, 1000)
+	}
+}
+
+// randomTrees plants count trees on distinct plots of the estate.
+func randomTrees(random *rand.Rand, width, length, count int) []Tree {
+	planted := make(map[Plot]bool, count)
+	trees := make([]Tree, 0, count)
+	for len(trees) < count {
+		plot := Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1}
+		if planted[plot] {
+			continue
+		}
+		planted[plot] = true
+		trees = append(trees, Tree{X: plot.X, Y: plot.Y, Height: random.Intn(30) + 1})
+	}
+	return trees
+}
+
+// flyPlotByPlot flies the drone over every plot of the estate one at a
+// time and records the waypoints it passes.
+func flyPlotByPlot(width, length int, trees []Tree) []Waypoint {
+	heights := make(map[Plot]int, len(trees))
+	for _, tree := range trees {
+		heights[Plot{X: tree.X, Y: tree.Y}] = tree.Height
+	}
+
+	waypoints := []Waypoint{{X: 1, Y: 1}}
+	for y := 1; y <= length; y++ {
+		for i := 1; i <= width; i++ {
+			x := i
+			if y%2 == 0 {
+				x = width - i + 1
+			}
+
+			last := waypoints[len(waypoints)-1]
+			altitude := heights[Plot{X: x, Y: y}] + DefaultClearance
+			distance := last.Distance + abs(altitude-last.Altitude)
+			if len(waypoints) > 1 {
+				distance += PlotSize
+			}
+			waypoints = append(waypoints, Waypoint{X: x, Y: y, Altitude: altitude, Distance: distance})
+		}
+	}
+
+	last := waypoints[len(waypoints)-1]
+	return append(waypoints, Waypoint{X: last.X, Y: last.Y, Distance: last.Distance + last.Altitude})
+}
@@ -0,0 +1,141 @@
+package droneplan
+
+import "github.com/pebruwantoro/technical-test-sawitpro/geo"
+
+// MAVLink values used by the QGroundControl mission file.
+const (
+	mavCmdNavWaypoint = 16
+	mavCmdNavLand     = 21
+	mavCmdNavTakeoff  = 22
+
+	mavFrameGlobalRelativeAlt = 3
+	mavFrameLocalENU          = 4
+
+	mavAutopilotGeneric = 0
+	mavTypeQuadrotor    = 2
+
+	qgcAltitudeModeRelative = 1
+
+	qgcCruiseSpeed = 15
+	qgcHoverSpeed  = 5
+)
+
+// QGCPlan is a QGroundControl .plan file holding the survey mission.
+type QGCPlan struct {
+	FileType      string         `json:"fileType"`
+	Version       int            `json:"version"`
+	GroundStation string         `json:"groundStation"`
+	Mission       QGCMission     `json:"mission"`
+	GeoFence      QGCGeoFence    `json:"geoFence"`
+	RallyPoints   QGCRallyPoints `json:"rallyPoints"`
+}
+
+// QGCMission is the mission section of a QGroundControl .plan file.
+type QGCMission struct {
+	Version             int              `json:"version"`
+	FirmwareType        int              `json:"firmwareType"`
+	VehicleType         int              `json:"vehicleType"`
+	CruiseSpeed         float64          `json:"cruiseSpeed"`
+	HoverSpeed          float64          `json:"hoverSpeed"`
+	PlannedHomePosition [3]float64       `json:"plannedHomePosition"`
+	Items               []QGCMissionItem `json:"items"`
+}
+
+// QGCMissionItem is a single MAVLink command of the mission.
+type QGCMissionItem struct {
+	Type                string     `json:"type"`
+	AutoContinue        bool       `json:"autoContinue"`
+	Command             int        `json:"command"`
+	DoJumpId            int        `json:"doJumpId"`
+	Frame               int        `json:"frame"`
+	Params              [7]float64 `json:"params"`
+	Altitude            float64    `json:"Altitude"`
+	AltitudeMode        int        `json:"AltitudeMode"`
+	AMSLAltAboveTerrain *float64   `json:"AMSLAltAboveTerrain"`
+}
+
+// QGCGeoFence is the empty geofence section of a QGroundControl .plan file.
+type QGCGeoFence struct {
+	Circles  []interface{} `json:"circles"`
+	Polygons []interface{} `json:"polygons"`
+	Version  int           `json:"version"`
+}
+
+// QGCRallyPoints is the empty rally point section of a QGroundControl .plan
+// file.
+type QGCRallyPoints struct {
+	Points  []interface{} `json:"points"`
+	Version int           `json:"version"`
+}
+
+// QGC returns the plan as a QGroundControl mission: a take-off over the
+// first plot, a waypoint at every corner of the route and a landing on the
+// last plot. Altitudes are relative to the take-off point. When the estate
+// is placed on the globe by reference, positions are latitudes and
+// longitudes; otherwise they are metres east and north of the first plot
+// in the local ENU frame.
+func (p *Plan) QGC(reference *geo.Reference) QGCPlan {
+	route := p.Route()
+
+	frame := mavFrameLocalENU
+	home := [3]float64{}
+	if reference != nil {
+		frame = mavFrameGlobalRelativeAlt
+		start := reference.Plot(route[0].X, route[0].Y, PlotSize, 0)
+		home = [3]float64{start.Latitude, start.Longitude, 0}
+	}
+
+	items := make([]QGCMissionItem, 0, len(route)-1)
+	for i := 1; i < len(route); i++ {
+		command := mavCmdNavWaypoint
+		switch i {
+		case 1:
+			command = mavCmdNavTakeoff
+		case len(route) - 1:
+			command = mavCmdNavLand
+		}
+
+		waypoint := route[i]
+		altitude := float64(waypoint.Altitude - route[0].Altitude)
+		params := [7]float64{0, 0, 0, 0, float64(PlotSize * (waypoint.X - 1)), float64(PlotSize * (waypoint.Y - 1)), altitude}
+		if reference != nil {
+			position := reference.Plot(waypoint.X, waypoint.Y, PlotSize, altitude)
+			params[4], params[5] = position.Latitude, position.Longitude
+		}
+
+		items = append(items, QGCMissionItem{
+			Type:         "SimpleItem",
+			AutoContinue: true,
+			Command:      command,
+			DoJumpId:     len(items) + 1,
+			Frame:        frame,
+			Params:       params,
+			Altitude:     altitude,
+			AltitudeMode: qgcAltitudeModeRelative,
+		})
+	}
+
+	return QGCPlan{
+		FileType:      "Plan",
+		Version:       1,
+		GroundStation: "QGroundControl",
+		Mission: QGCMission{
+			Version:             2,
+			FirmwareType:        mavAutopilotGeneric,
+			VehicleType:         mavTypeQuadrotor,
+			CruiseSpeed:         qgcCruiseSpeed,
+			HoverSpeed:          qgcHoverSpeed,
+			PlannedHomePosition: home,
+			Items:               items,
+		},
+		GeoFence: QGCGeoFence{
+			Circles:  []interface{}{},
+			Polygons: []interface{}{},
+			Version:  2,
+		},
+		RallyPoints: QGCRallyPoints{
+			Points:  []interface{}{},
+			Version: 2,
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package droneplan
+
+import (
+	"testing"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/geo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQGC(t *testing.T) {
+	plan := New(Options{Width: 5, Length: 1, Trees: rowTrees}).QGC(nil)
+
+	assert.Equal(t, "Plan", plan.FileType)
+	assert.Equal(t, "QGroundControl", plan.GroundStation)
+
+	commands := []int{}
+	params := [][7]float64{}
+	for i, item := range plan.Mission.Items {
+		assert.Equal(t, i+1, item.DoJumpId)
+		assert.Equal(t, mavFrameLocalENU, item.Frame)
+		commands = append(commands, item.Command)
+		params = append(params, item.Params)
+	}
+
+	assert.Equal(t, []int{
+		mavCmdNavTakeoff,
+		mavCmdNavWaypoint,
+		mavCmdNavWaypoint,
+		mavCmdNavWaypoint,
+		mavCmdNavWaypoint,
+		mavCmdNavLand,
+	}, commands)
+	assert.Equal(t, [][7]float64{
+		{0, 0, 0, 0, 0, 0, 6},
+		{0, 0, 0, 0, 20, 0, 6},
+		{0, 0, 0, 0, 20, 0, 4},
+		{0, 0, 0, 0, 20, 0, 5},
+		{0, 0, 0, 0, 40, 0, 5},
+		{0, 0, 0, 0, 40, 0, 0},
+	}, params)
+}
+
+func TestQGCGeoReferenced(t *testing.T) {
+	reference := geo.Reference{Latitude: -1.5, Longitude: 102.1, Bearing: 30}
+	plan := New(Options{Width: 5, Length: 1, Trees: rowTrees}).QGC(&reference)
+
+	start := reference.Plot(1, 1, PlotSize, 0)
+	assert.Equal(t, [3]float64{start.Latitude, start.Longitude, 0}, plan.Mission.PlannedHomePosition)
+
+	landing := reference.Plot(5, 1, PlotSize, 0)
+	last := plan.Mission.Items[len(plan.Mission.Items)-1]
+	assert.Equal(t, mavFrameGlobalRelativeAlt, last.Frame)
+	assert.Equal(t, [7]float64{0, 0, 0, 0, landing.Latitude, landing.Longitude, 0}, last.Params)
+}
@@ -0,0 +1,160 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegion(t *testing.T) {
+	testCases := []struct {
+		name         string
+		width        int
+		length       int
+		region       Area
+		trees        []Tree
+		zones        []Area
+		reachable    bool
+		distance     int
+		skippedPlots int
+		bounds       Area
+	}{
+		{
+			name:   "Region_Ferry_From_The_Entry_And_Back",
+			width:  5,
+			length: 5,
+			region: Area{From: Plot{X: 3, Y: 3}, To: Plot{X: 4, Y: 4}},
+			// 1 plot at the entry, 3 ferried, 4 surveyed, 4 ferried back
+			// and 1 at the entry.
+			reachable: true,
+			distance:  122,
+			bounds:    Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 4, Y: 4}},
+		},
+		{
+			name:      "Region_Holding_The_Entry",
+			width:     5,
+			length:    5,
+			region:    Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 2, Y: 2}},
+			reachable: true,
+			distance:  42,
+			bounds:    Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 2, Y: 2}},
+		},
+		{
+			name:      "Region_Corners_In_Any_Order_Clipped_To_The_Estate",
+			width:     5,
+			length:    5,
+			region:    Area{From: Plot{X: 9, Y: 9}, To: Plot{X: 4, Y: 4}},
+			reachable: true,
+			distance:  162,
+			bounds:    Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 5, Y: 5}},
+		},
+		{
+			name:      "Region_Climb_Over_Trees_On_The_Ferry",
+			width:     3,
+			length:    1,
+			region:    Area{From: Plot{X: 3, Y: 1}, To: Plot{X: 3, Y: 1}},
+			trees:     []Tree{{X: 2, Y: 1, Height: 5}},
+			reachable: true,
+			distance:  62,
+			bounds:    Area{From: Plot{X: 1, Y: 1}, To: Plot{X: 3, Y: 1}},
+		},
+		{
+			name:         "Region_Entry_In_A_No_Fly_Zone",
+			width:        5,
+			length:       5,
+			region:       Area{From: Plot{X: 3, Y: 3}, To: Plot{X: 3, Y: 3}},
+			zones:        []Area{{From: Plot{X: 1, Y: 1}, To: Plot{X: 1, Y: 1}}},
+			skippedPlots: 1,
+		},
+		{
+			name:   "Region_Outside_The_Estate",
+			width:  5,
+			length: 5,
+			region: Area{From: Plot{X: 7, Y: 7}, To: Plot{X: 8, Y: 8}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			region := tc.region
+			plan := New(Options{Width: tc.width, Length: tc.length, Trees: tc.trees, NoFlyZones: tc.zones, Region: &region})
+			assert.Equal(t, tc.reachable, plan.Reachable())
+			assert.Equal(t, tc.skippedPlots, plan.SkippedPlots())
+			if tc.reachable {
+				assert.Equal(t, tc.distance, plan.Distance())
+				assert.Equal(t, tc.bounds, plan.Bounds())
+			}
+		})
+	}
+}
+
+func TestRegionPath(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		width := random.Intn(10) + 1
+		length := random.Intn(10) + 1
+		region := Area{
+			From: Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+			To:   Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+		}
+		zones := make([]Area, random.Intn(3))
+		for j := range zones {
+			zones[j] = Area{
+				From: Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+				To:   Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+			}
+		}
+
+		plan := New(Options{Width: width, Length: length, NoFlyZones: zones, Region: &region})
+		region = region.normalize()
+		closed := func(plot Plot) bool {
+			for _, zone := range zones {
+				if zone.normalize().contains(plot) {
+					return true
+				}
+			}
+			return false
+		}
+		entry := Plot{X: 1, Y: 1}
+
+		// Every free plot of the region the drone can reach from the
+		// entry is surveyed once, and no plot outside the region is.
+		surveyed := make(map[Plot]bool)
+		for _, s := range plan.path.segments {
+			for offset := 0; s.surveys() && offset < s.len(); offset++ {
+				plot := s.plot(offset)
+				assert.True(t, region.contains(plot))
+				assert.False(t, surveyed[plot])
+				surveyed[plot] = true
+			}
+		}
+		for x := region.From.X; x <= region.To.X; x++ {
+			for y := region.From.Y; y <= region.To.Y; y++ {
+				plot := Plot{X: x, Y: y}
+				reachable := !closed(entry) && !closed(plot) && shortestFlight(width, length, closed, entry, plot) >= 0
+				assert.Equal(t, reachable, surveyed[plot])
+			}
+		}
+		assert.Equal(t, region.width()*region.length()-len(surveyed), plan.SkippedPlots())
+
+		if !plan.Reachable() {
+			continue
+		}
+
+		// The drone takes off and lands at the entry, moves one plot at a
+		// time and never flies over a zone or out of the bounds.
+		assert.Equal(t, entry, plan.path.plot(0))
+		assert.Equal(t, entry, plan.path.plot(plan.path.len()-1))
+		for position := 0; position < plan.path.len(); position++ {
+			plot := plan.path.plot(position)
+			assert.False(t, closed(plot))
+			assert.True(t, plan.Bounds().contains(plot))
+			if position > 0 {
+				previous := plan.path.plot(position - 1)
+				assert.Equal(t, 1, abs(plot.X-previous.X)+abs(plot.Y-previous.Y))
+			}
+		}
+	}
+}
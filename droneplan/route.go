@@ -0,0 +1,99 @@
+package droneplan
+
+import "sort"
+
+// Route returns the corners of the drone flight: the take-off point, every
+// point where the drone turns, climbs or descends, and the landing point.
+// The drone flies straight between two corners, either horizontally or
+// vertically. It climbs before it flies over a taller plot and descends
+// only once it has left a taller plot, so it never clips a tree.
+//
+// A route has O(t + l) corners for t trees on an estate of l rows.
+func (p *Plan) Route() []Waypoint {
+	route := []Waypoint{}
+	add := func(plot Plot, altitude int) {
+		next := Waypoint{X: plot.X, Y: plot.Y, Altitude: altitude}
+		if len(route) > 0 {
+			last := route[len(route)-1]
+			next.Distance = last.Distance + PlotSize*(abs(next.X-last.X)+abs(next.Y-last.Y)) + abs(next.Altitude-last.Altitude)
+		}
+
+		// Drop the last corner when the drone keeps flying in the same
+		// direction through it.
+		if len(route) > 1 && direction(route[len(route)-2], route[len(route)-1]) == direction(route[len(route)-1], next) {
+			route[len(route)-1] = next
+			return
+		}
+		route = append(route, next)
+	}
+
+	keys := p.keyPositions()
+	take, land := p.path.plot(keys[0]), p.path.plot(keys[len(keys)-1])
+	add(take, p.groundOf(take))
+	add(take, p.altitudeAt(keys[0]))
+
+	for i := 1; i < len(keys); i++ {
+		from, to := keys[i-1], keys[i]
+		fromAltitude, toAltitude := p.altitudeAt(from), p.altitudeAt(to)
+
+		switch {
+		case toAltitude > fromAltitude:
+			add(p.path.plot(from), toAltitude)
+		case toAltitude < fromAltitude:
+			add(p.path.plot(to), fromAltitude)
+		}
+		add(p.path.plot(to), toAltitude)
+	}
+
+	add(land, p.groundOf(land))
+
+	return route
+}
+
+// keyPositions returns, in path order, the positions where the drone may
+// change direction or altitude: the ends of every row and ferry line, every
+// plot holding a tree, an obstacle or ground off the base elevation and the
+// plots next to it. The plots between two consecutive key positions are all
+// flown straight at the cruise altitude.
+func (p *Plan) keyPositions() []int {
+	last := p.path.len() - 1
+	keys := map[int]bool{0: true, last: true}
+
+	for _, turn := range p.path.turns() {
+		keys[turn] = true
+	}
+
+	for _, s := range p.stops {
+		keys[s.position] = true
+		if s.position > 0 {
+			keys[s.position-1] = true
+		}
+		if s.position < last {
+			keys[s.position+1] = true
+		}
+	}
+
+	positions := make([]int, 0, len(keys))
+	for position := range keys {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+
+	return positions
+}
+
+// direction returns the unit step the drone takes to fly from one waypoint
+// to the next.
+func direction(from, to Waypoint) [3]int {
+	return [3]int{sign(to.X - from.X), sign(to.Y - from.Y), sign(to.Altitude - from.Altitude)}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
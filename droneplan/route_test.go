@@ -0,0 +1,95 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute(t *testing.T) {
+	testCases := []struct {
+		name   string
+		width  int
+		length int
+		trees  []Tree
+		route  []Waypoint
+	}{
+		{
+			name:   "Route_Single_Plot",
+			width:  1,
+			length: 1,
+			route: []Waypoint{
+				{X: 1, Y: 1, Altitude: 0, Distance: 0},
+				{X: 1, Y: 1, Altitude: 1, Distance: 1},
+				{X: 1, Y: 1, Altitude: 0, Distance: 2},
+			},
+		},
+		{
+			name:   "Route_Zigzag_Without_Trees",
+			width:  3,
+			length: 2,
+			route: []Waypoint{
+				{X: 1, Y: 1, Altitude: 0, Distance: 0},
+				{X: 1, Y: 1, Altitude: 1, Distance: 1},
+				{X: 3, Y: 1, Altitude: 1, Distance: 21},
+				{X: 3, Y: 2, Altitude: 1, Distance: 31},
+				{X: 1, Y: 2, Altitude: 1, Distance: 51},
+				{X: 1, Y: 2, Altitude: 0, Distance: 52},
+			},
+		},
+		{
+			name:   "Route_Climb_Before_And_Descend_After_Trees",
+			width:  5,
+			length: 1,
+			trees:  rowTrees,
+			route: []Waypoint{
+				{X: 1, Y: 1, Altitude: 0, Distance: 0},
+				{X: 1, Y: 1, Altitude: 6, Distance: 6},
+				{X: 3, Y: 1, Altitude: 6, Distance: 26},
+				{X: 3, Y: 1, Altitude: 4, Distance: 28},
+				{X: 3, Y: 1, Altitude: 5, Distance: 29},
+				{X: 5, Y: 1, Altitude: 5, Distance: 49},
+				{X: 5, Y: 1, Altitude: 0, Distance: 54},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.route, New(Options{Width: tc.width, Length: tc.length, Trees: tc.trees}).Route())
+		})
+	}
+}
+
+func TestRouteFliesStraightLegs(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		width := random.Intn(8) + 1
+		length := random.Intn(8) + 1
+		opts := Options{
+			Width:   width,
+			Length:  length,
+			Trees:   randomTrees(random, width, length, random.Intn(width*length+1)),
+			Pattern: Patterns[i/2%len(Patterns)],
+		}
+		if i%2 == 1 {
+			opts.NoFlyZones = []Area{{
+				From: Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+				To:   Plot{X: random.Intn(width) + 1, Y: random.Intn(length) + 1},
+			}}
+		}
+		plan := New(opts)
+		if !plan.Reachable() {
+			continue
+		}
+
+		route := plan.Route()
+		assert.Equal(t, plan.Distance(), route[len(route)-1].Distance)
+		for j := 1; j < len(route); j++ {
+			step := direction(route[j-1], route[j])
+			assert.Equal(t, 1, abs(step[0])+abs(step[1])+abs(step[2]))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package droneplan
+
+import "sort"
+
+// Section is a stretch of the path flown by one drone of a fleet. The drone
+// takes off at Start, surveys every plot up to End and lands there.
+type Section struct {
+	Start    Plot
+	End      Plot
+	Distance int
+
+	// start and end are the positions of Start and End on the path, as a
+	// ferry flight may cross a plot more than once.
+	start int
+	end   int
+}
+
+// Split divides the path into sections for the given number of drones. The
+// sections follow each other along the path and each drone flies roughly
+// the same distance. It returns nil when there are more drones than plots.
+func (p *Plan) Split(drones int) []Section {
+	plots := p.path.len()
+	if drones < 1 || drones > plots {
+		return nil
+	}
+
+	sections := make([]Section, 0, drones)
+	total := p.distanceAt(plots - 1)
+	start := 0
+	for i := 1; i <= drones; i++ {
+		end := plots - 1
+		if i < drones {
+			target := total * i / drones
+			end = sort.Search(plots, func(position int) bool {
+				return p.distanceAt(position) >= target
+			})
+			if end > 0 && target-p.distanceAt(end-1) < p.distanceAt(end)-target {
+				end--
+			}
+
+			// Every drone needs at least one plot of its own.
+			if end < start {
+				end = start
+			}
+			if last := plots - 1 - (drones - i); end > last {
+				end = last
+			}
+		}
+
+		sections = append(sections, Section{
+			Start:    p.path.plot(start),
+			End:      p.path.plot(end),
+			Distance: p.sectionDistance(start, end),
+			start:    start,
+			end:      end,
+		})
+		start = end + 1
+	}
+
+	return sections
+}
+
+// sectionDistance returns the distance a drone flies to take off above the
+// plot at position start, survey every plot up to position end and land.
+func (p *Plan) sectionDistance(start, end int) int {
+	return p.heightAt(start) + p.distanceAt(end) - p.distanceAt(start) + p.heightAt(end)
+}
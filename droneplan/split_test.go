@@ -0,0 +1,97 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		width    int
+		length   int
+		trees    []Tree
+		drones   int
+		sections []Section
+	}{
+		{
+			name:   "Split_One_Drone_Flies_The_Whole_Plan",
+			width:  5,
+			length: 1,
+			trees:  rowTrees,
+			drones: 1,
+			sections: []Section{
+				{Start: Plot{X: 1, Y: 1}, End: Plot{X: 5, Y: 1}, Distance: 54, start: 0, end: 4},
+			},
+		},
+		{
+			name:   "Split_Two_Drones",
+			width:  5,
+			length: 1,
+			trees:  rowTrees,
+			drones: 2,
+			sections: []Section{
+				{Start: Plot{X: 1, Y: 1}, End: Plot{X: 3, Y: 1}, Distance: 32, start: 0, end: 2},
+				{Start: Plot{X: 4, Y: 1}, End: Plot{X: 5, Y: 1}, Distance: 20, start: 3, end: 4},
+			},
+		},
+		{
+			name:   "Split_Across_Rows",
+			width:  2,
+			length: 2,
+			drones: 2,
+			sections: []Section{
+				{Start: Plot{X: 1, Y: 1}, End: Plot{X: 2, Y: 1}, Distance: 12, start: 0, end: 1},
+				{Start: Plot{X: 2, Y: 2}, End: Plot{X: 1, Y: 2}, Distance: 12, start: 2, end: 3},
+			},
+		},
+		{
+			name:   "Split_One_Plot_Per_Drone",
+			width:  3,
+			length: 1,
+			drones: 3,
+			sections: []Section{
+				{Start: Plot{X: 1, Y: 1}, End: Plot{X: 1, Y: 1}, Distance: 2, start: 0, end: 0},
+				{Start: Plot{X: 2, Y: 1}, End: Plot{X: 2, Y: 1}, Distance: 2, start: 1, end: 1},
+				{Start: Plot{X: 3, Y: 1}, End: Plot{X: 3, Y: 1}, Distance: 2, start: 2, end: 2},
+			},
+		},
+		{
+			name:   "Split_More_Drones_Than_Plots",
+			width:  2,
+			length: 1,
+			drones: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.sections, New(Options{Width: tc.width, Length: tc.length, Trees: tc.trees}).Split(tc.drones))
+		})
+	}
+}
+
+func TestSplitCoversEveryPlotOnce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		width := random.Intn(8) + 1
+		length := random.Intn(8) + 1
+		plan := New(Options{Width: width, Length: length, Trees: randomTrees(random, width, length, random.Intn(width*length+1))})
+		drones := random.Intn(width*length) + 1
+
+		sections := plan.Split(drones)
+		assert.Len(t, sections, drones)
+
+		next := 0
+		for _, section := range sections {
+			assert.Equal(t, next, section.start)
+			assert.Equal(t, section.Start, plan.path.plot(section.start))
+			assert.Equal(t, section.End, plan.path.plot(section.end))
+			next = section.end + 1
+		}
+		assert.Equal(t, width*length, next)
+	}
+}
@@ -0,0 +1,180 @@
+package droneplan
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// MIMESVG is the media type of the picture SVG returns.
+const MIMESVG = "image/svg+xml"
+
+// Sizes of the SVG picture in pixels.
+const (
+	svgPlotSize      = 20
+	svgMargin        = 30
+	svgMinWidth      = 400
+	svgProfileHeight = 200
+)
+
+// SVG draws the plan as an SVG picture of two panels. The top panel is the
+// estate seen from above, north up: every tree coloured by its height,
+// from light green for the shortest to dark green for the tallest, the
+// obstacles in grey, the no-fly zones in red and the drone path on top. The
+// bottom panel is the altitude profile: the altitude of the drone and of
+// the ground under it against the horizontal distance flown. It must not be
+// called on a plan that is not reachable.
+func (p *Plan) SVG() []byte {
+	route := p.Route()
+	mapWidth := p.estate.width() * svgPlotSize
+	mapHeight := p.estate.length() * svgPlotSize
+	panelWidth := max(mapWidth, svgMinWidth)
+	profileTop := svgMargin + mapHeight + 2*svgMargin
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		panelWidth+2*svgMargin, profileTop+svgProfileHeight+svgMargin, panelWidth+2*svgMargin, profileTop+svgProfileHeight+svgMargin)
+	fmt.Fprintf(&b, `<text x="%d" y="%d">Drone plan, %d m</text>`+"\n", svgMargin, svgMargin-10, p.Distance())
+
+	p.svgEstate(&b, route)
+	p.svgProfile(&b, route, profileTop, panelWidth)
+
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// svgEstate draws the estate panel.
+func (p *Plan) svgEstate(b *bytes.Buffer, route []Waypoint) {
+	fmt.Fprintf(b, `<g transform="translate(%d %d)">`+"\n", svgMargin, svgMargin)
+	fmt.Fprintf(b, `<defs><pattern id="plot" width="%d" height="%d" patternUnits="userSpaceOnUse"><path d="M %d 0 L 0 0 0 %d" fill="none" stroke="#d9d9d9"/></pattern></defs>`+"\n",
+		svgPlotSize, svgPlotSize, svgPlotSize, svgPlotSize)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="#f7f7f7"/>`+"\n", p.estate.width()*svgPlotSize, p.estate.length()*svgPlotSize)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="url(#plot)" stroke="#969696"/>`+"\n", p.estate.width()*svgPlotSize, p.estate.length()*svgPlotSize)
+
+	for _, zone := range p.noFlyZones {
+		x, y := p.svgCorner(Plot{X: zone.From.X, Y: zone.To.Y})
+		fmt.Fprintf(b, `<rect class="no-fly" x="%d" y="%d" width="%d" height="%d" fill="#e41a1c" fill-opacity="0.25" stroke="#e41a1c"/>`+"\n",
+			x, y, zone.width()*svgPlotSize, zone.length()*svgPlotSize)
+	}
+
+	for _, obstacle := range p.obstacles {
+		plot := Plot{X: obstacle.X, Y: obstacle.Y}
+		if !p.estate.contains(plot) {
+			continue
+		}
+		x, y := p.svgCorner(plot)
+		fmt.Fprintf(b, `<rect class="obstacle" x="%d" y="%d" width="%d" height="%d" fill="#636363"><title>Obstacle (%d, %d), %d m</title></rect>`+"\n",
+			x, y, svgPlotSize, svgPlotSize, plot.X, plot.Y, obstacle.Height)
+	}
+
+	tallest := 0
+	for _, tree := range p.trees {
+		tallest = max(tallest, tree.Height)
+	}
+	for _, tree := range p.trees {
+		plot := Plot{X: tree.X, Y: tree.Y}
+		if !p.estate.contains(plot) {
+			continue
+		}
+		x, y := p.svgCorner(plot)
+		fmt.Fprintf(b, `<rect class="tree" x="%d" y="%d" width="%d" height="%d" fill="%s"><title>Tree (%d, %d), %d m</title></rect>`+"\n",
+			x, y, svgPlotSize, svgPlotSize, treeColour(tree.Height, tallest), plot.X, plot.Y, tree.Height)
+	}
+
+	points := make([]string, 0, len(route))
+	for i, waypoint := range route {
+		if i > 0 && waypoint.X == route[i-1].X && waypoint.Y == route[i-1].Y {
+			continue
+		}
+		x, y := p.svgCentre(Plot{X: waypoint.X, Y: waypoint.Y})
+		points = append(points, fmt.Sprintf("%d,%d", x, y))
+	}
+	fmt.Fprintf(b, `<polyline class="path" points="%s" fill="none" stroke="#2171b5" stroke-width="2"/>`+"\n", strings.Join(points, " "))
+
+	takeOffX, takeOffY := p.svgCentre(Plot{X: route[0].X, Y: route[0].Y})
+	landingX, landingY := p.svgCentre(Plot{X: route[len(route)-1].X, Y: route[len(route)-1].Y})
+	fmt.Fprintf(b, `<circle cx="%d" cy="%d" r="5" fill="#ffffff" stroke="#2171b5" stroke-width="2"><title>Take off</title></circle>`+"\n", takeOffX, takeOffY)
+	fmt.Fprintf(b, `<circle cx="%d" cy="%d" r="5" fill="#2171b5"><title>Landing</title></circle>`+"\n", landingX, landingY)
+	b.WriteString("</g>\n")
+}
+
+// svgProfile draws the altitude profile panel at the given top.
+func (p *Plan) svgProfile(b *bytes.Buffer, route []Waypoint, top, width int) {
+	// The drone flies straight between two corners of the route, so the
+	// horizontal distance to a corner adds up the plots between them.
+	horizontal := make([]int, len(route))
+	for i := 1; i < len(route); i++ {
+		horizontal[i] = horizontal[i-1] + PlotSize*(abs(route[i].X-route[i-1].X)+abs(route[i].Y-route[i-1].Y))
+	}
+	total := max(horizontal[len(horizontal)-1], 1)
+
+	keys := p.keyPositions()
+	low, high := route[0].Altitude, route[0].Altitude
+	for _, waypoint := range route {
+		low, high = min(low, waypoint.Altitude), max(high, waypoint.Altitude)
+	}
+	for _, position := range keys {
+		low = min(low, p.groundOf(p.path.plot(position)))
+	}
+	if high == low {
+		high = low + 1
+	}
+
+	x := func(distance int) string {
+		return fmt.Sprintf("%.1f", float64(distance)*float64(width)/float64(total))
+	}
+	y := func(altitude int) string {
+		return fmt.Sprintf("%.1f", float64(svgProfileHeight)-float64(altitude-low)*svgProfileHeight/float64(high-low))
+	}
+
+	fmt.Fprintf(b, `<g transform="translate(%d %d)">`+"\n", svgMargin, top)
+	fmt.Fprintf(b, `<text y="-10">Altitude profile</text>`+"\n")
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="#ffffff" stroke="#969696"/>`+"\n", width, svgProfileHeight)
+
+	ground := make([]string, 0, len(keys)+2)
+	for _, position := range keys {
+		ground = append(ground, x(PlotSize*position)+","+y(p.groundOf(p.path.plot(position))))
+	}
+	ground = append(ground, x(total)+","+y(low), x(0)+","+y(low))
+	fmt.Fprintf(b, `<polygon class="ground" points="%s" fill="#a6761d" fill-opacity="0.4"/>`+"\n", strings.Join(ground, " "))
+
+	altitude := make([]string, 0, len(route))
+	for i, waypoint := range route {
+		altitude = append(altitude, x(horizontal[i])+","+y(waypoint.Altitude))
+	}
+	fmt.Fprintf(b, `<polyline class="altitude" points="%s" fill="none" stroke="#2171b5" stroke-width="2"/>`+"\n", strings.Join(altitude, " "))
+
+	fmt.Fprintf(b, `<text x="-4" y="4" text-anchor="end">%d m</text>`+"\n", high)
+	fmt.Fprintf(b, `<text x="-4" y="%d" text-anchor="end">%d m</text>`+"\n", svgProfileHeight+4, low)
+	fmt.Fprintf(b, `<text y="%d">0 m</text>`+"\n", svgProfileHeight+16)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%d m</text>`+"\n", width, svgProfileHeight+16, horizontal[len(horizontal)-1])
+	b.WriteString("</g>\n")
+}
+
+// svgCorner returns where the north-west corner of the plot lies in the
+// estate panel, and svgCentre where its centre lies.
+func (p *Plan) svgCorner(plot Plot) (int, int) {
+	return (plot.X - p.estate.From.X) * svgPlotSize, (p.estate.To.Y - plot.Y) * svgPlotSize
+}
+
+func (p *Plan) svgCentre(plot Plot) (int, int) {
+	x, y := p.svgCorner(plot)
+	return x + svgPlotSize/2, y + svgPlotSize/2
+}
+
+// treeColour shades a tree from light green to dark green as its height
+// grows towards the tallest.
+func treeColour(height, tallest int) string {
+	light := [3]float64{199, 233, 192}
+	dark := [3]float64{0, 68, 27}
+
+	share := 1.0
+	if tallest > 1 {
+		share = float64(height-1) / float64(tallest-1)
+	}
+	var colour [3]int
+	for i := range colour {
+		colour[i] = int(light[i] + (dark[i]-light[i])*share + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", colour[0], colour[1], colour[2])
+}
@@ -0,0 +1,67 @@
+package droneplan
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSVG(t *testing.T) {
+	plan := New(Options{
+		Width:      3,
+		Length:     3,
+		Trees:      []Tree{{X: 2, Y: 1, Height: 4}, {X: 3, Y: 3, Height: 2}},
+		Obstacles:  []Obstacle{{X: 1, Y: 3, Height: 8}},
+		NoFlyZones: []Area{{From: Plot{X: 2, Y: 2}, To: Plot{X: 3, Y: 2}}},
+	})
+
+	// Every element the picture is made of, by class.
+	elements := make(map[string][]map[string]string)
+	decoder := xml.NewDecoder(bytes.NewReader(plan.SVG()))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+		if class, ok := attrs["class"]; ok {
+			elements[class] = append(elements[class], attrs)
+		}
+	}
+
+	assert.Equal(t, []map[string]string{
+		{"class": "tree", "x": "20", "y": "40", "width": "20", "height": "20", "fill": "#00441b"},
+		{"class": "tree", "x": "40", "y": "0", "width": "20", "height": "20", "fill": "#85b289"},
+	}, elements["tree"])
+	assert.Len(t, elements["obstacle"], 1)
+	assert.Equal(t, "0", elements["obstacle"][0]["x"])
+	assert.Equal(t, "0", elements["obstacle"][0]["y"])
+	assert.Len(t, elements["no-fly"], 1)
+	assert.Equal(t, "20", elements["no-fly"][0]["x"])
+	assert.Equal(t, "20", elements["no-fly"][0]["y"])
+	assert.Equal(t, "40", elements["no-fly"][0]["width"])
+	assert.Equal(t, "20", elements["no-fly"][0]["height"])
+
+	// The drone surveys the west column northwards and the north row of
+	// the rest eastwards, then ferries back around the zone to survey the
+	// south row.
+	assert.Len(t, elements["path"], 1)
+	assert.Equal(t, "10,50 10,30 10,10 30,10 50,10 30,10 10,10 10,30 10,50 50,50", elements["path"][0]["points"])
+	assert.Len(t, elements["altitude"], 1)
+	assert.Len(t, elements["ground"], 1)
+}
@@ -0,0 +1,98 @@
+package droneplan
+
+import (
+	"math"
+	"time"
+)
+
+// Sample is one position logged by the drone during a flight. X and Y are
+// in plots, so (2, 3) is the centre of plot (2, 3), and the altitude is in
+// metres above the take-off point, as drones log it.
+type Sample struct {
+	Time     time.Time
+	X        float64
+	Y        float64
+	Altitude float64
+}
+
+// Violation is a sample logged over a tree or an obstacle lower than the
+// clearance the plan keeps above it.
+type Violation struct {
+	Sample
+	// Required is the lowest altitude allowed above the plot, above the
+	// take-off point like the sample.
+	Required int
+}
+
+// Deviations is how far a logged flight strayed from the plan.
+type Deviations struct {
+	// MissedPlotCount is the number of plots to survey the drone never
+	// flew over, and MissedPlots the first of them in path order.
+	MissedPlotCount int
+	MissedPlots     []Plot
+	// ViolationCount is the number of samples logged too low over a tree
+	// or an obstacle, and Violations the first of them in time order.
+	ViolationCount int
+	Violations     []Violation
+	// Distance is the distance in metres flown between the samples.
+	Distance float64
+}
+
+// Compare checks a flight log, sorted by time, against the plan. The
+// samples are matched to the plot they lie on and samples off the path are
+// only counted in the distance. The take-off and landing plots
+// are not checked for altitude, as the drone takes off and lands there. At
+// most limit missed plots and violations are listed.
+func (p *Plan) Compare(samples []Sample, limit int) Deviations {
+	deviations := Deviations{
+		MissedPlots: []Plot{},
+		Violations:  []Violation{},
+	}
+
+	takeOff, landing := p.path.plot(0), p.path.plot(p.path.len()-1)
+	home := p.groundOf(takeOff)
+	visited := make(map[Plot]bool)
+	for i, sample := range samples {
+		if i > 0 {
+			previous := samples[i-1]
+			deviations.Distance += math.Sqrt(
+				math.Pow(PlotSize*(sample.X-previous.X), 2) +
+					math.Pow(PlotSize*(sample.Y-previous.Y), 2) +
+					math.Pow(sample.Altitude-previous.Altitude, 2),
+			)
+		}
+
+		plot := Plot{X: int(math.Round(sample.X)), Y: int(math.Round(sample.Y))}
+		visited[plot] = true
+		if plot == takeOff || plot == landing {
+			continue
+		}
+
+		if required, ok := p.occupied[plot]; ok && sample.Altitude < float64(required-home) {
+			required -= home
+			deviations.ViolationCount++
+			if len(deviations.Violations) < limit {
+				deviations.Violations = append(deviations.Violations, Violation{Sample: sample, Required: required})
+			}
+		}
+	}
+
+	deviations.MissedPlotCount = p.surveyed
+	for plot := range visited {
+		if p.path.surveys(plot) {
+			deviations.MissedPlotCount--
+		}
+	}
+	for _, s := range p.path.segments {
+		if !s.surveys() {
+			continue
+		}
+		for offset := 0; offset < s.len() && len(deviations.MissedPlots) < limit && len(deviations.MissedPlots) < deviations.MissedPlotCount; offset++ {
+			if plot := s.plot(offset); !visited[plot] {
+				deviations.MissedPlots = append(deviations.MissedPlots, plot)
+			}
+		}
+	}
+
+	return deviations
+}
@@ -0,0 +1,81 @@
+package droneplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	plan := New(Options{Width: 5, Length: 1, Trees: rowTrees})
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	sample := func(second int, x, y, altitude float64) Sample {
+		return Sample{Time: start.Add(time.Duration(second) * time.Second), X: x, Y: y, Altitude: altitude}
+	}
+
+	testCases := []struct {
+		name       string
+		samples    []Sample
+		limit      int
+		deviations Deviations
+	}{
+		{
+			name: "Compare_Flight_As_Planned",
+			samples: []Sample{
+				sample(0, 1, 1, 0),
+				sample(1, 1, 1, 6),
+				sample(2, 2, 1, 6),
+				sample(3, 3, 1, 6),
+				sample(4, 4, 1, 6),
+				sample(5, 5, 1, 6),
+				sample(6, 5, 1, 0),
+			},
+			limit: 10,
+			deviations: Deviations{
+				MissedPlots: []Plot{},
+				Violations:  []Violation{},
+				Distance:    52,
+			},
+		},
+		{
+			name: "Compare_Flight_Too_Low_And_Cut_Short",
+			samples: []Sample{
+				sample(0, 1, 1, 0),
+				sample(1, 1, 1, 4),
+				sample(2, 2.2, 1, 4),
+				sample(3, 2.6, 1, 4),
+				sample(4, 2.6, 1, 0),
+			},
+			limit: 1,
+			deviations: Deviations{
+				MissedPlotCount: 2,
+				MissedPlots:     []Plot{{X: 4, Y: 1}},
+				ViolationCount:  2,
+				Violations: []Violation{
+					{Sample: sample(2, 2.2, 1, 4), Required: 6},
+				},
+				Distance: 24,
+			},
+		},
+		{
+			name:  "Compare_Empty_Log",
+			limit: 10,
+			deviations: Deviations{
+				MissedPlotCount: 5,
+				MissedPlots:     []Plot{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 5, Y: 1}},
+				Violations:      []Violation{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deviations := plan.Compare(tc.samples, tc.limit)
+			assert.InDelta(t, tc.deviations.Distance, deviations.Distance, 1e-9)
+
+			deviations.Distance = tc.deviations.Distance
+			assert.Equal(t, tc.deviations, deviations)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package droneplan
+
+import "math"
+
+// Interpolate fills in the ground elevation of every plot of an estate of
+// width by length plots from a few surveyed plots, by inverse distance
+// weighting: every survey point weighs in by the inverse square of its
+// distance to the plot. Surveyed plots keep their own elevation. The
+// elevations are rounded to the metre and returned row by row from the
+// south-west corner. Survey points outside the estate still weigh in.
+func Interpolate(width, length int, points []Elevation) []Elevation {
+	if len(points) == 0 {
+		return nil
+	}
+
+	elevations := make([]Elevation, 0, width*length)
+	for y := 1; y <= length; y++ {
+		for x := 1; x <= width; x++ {
+			elevations = append(elevations, Elevation{X: x, Y: y, Elevation: interpolateAt(x, y, points)})
+		}
+	}
+	return elevations
+}
+
+func interpolateAt(x, y int, points []Elevation) int {
+	var weighted, weights float64
+	for _, point := range points {
+		dx, dy := float64(point.X-x), float64(point.Y-y)
+		squared := dx*dx + dy*dy
+		if squared == 0 {
+			return point.Elevation
+		}
+		weighted += float64(point.Elevation) / squared
+		weights += 1 / squared
+	}
+	return int(math.Round(weighted / weights))
+}
@@ -0,0 +1,169 @@
+package droneplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerrain(t *testing.T) {
+	testCases := []struct {
+		name       string
+		width      int
+		base       int
+		elevations []Elevation
+		trees      []Tree
+		distance   int
+		landing    Waypoint
+	}{
+		{
+			name:     "Terrain_Flat_At_The_Base_Elevation",
+			width:    3,
+			base:     100,
+			distance: 22,
+			landing:  Waypoint{X: 3, Y: 1, Altitude: 100, Distance: 22},
+		},
+		{
+			name:       "Terrain_Follows_A_Hill",
+			width:      3,
+			base:       100,
+			elevations: []Elevation{{X: 2, Y: 1, Elevation: 104}},
+			distance:   30,
+			landing:    Waypoint{X: 3, Y: 1, Altitude: 100, Distance: 30},
+		},
+		{
+			name:       "Terrain_Tree_On_A_Hill",
+			width:      3,
+			base:       100,
+			elevations: []Elevation{{X: 2, Y: 1, Elevation: 104}},
+			trees:      []Tree{{X: 2, Y: 1, Height: 5}},
+			distance:   40,
+			landing:    Waypoint{X: 3, Y: 1, Altitude: 100, Distance: 40},
+		},
+		{
+			name:       "Terrain_Land_In_A_Hollow",
+			width:      2,
+			base:       100,
+			elevations: []Elevation{{X: 2, Y: 1, Elevation: 90}},
+			distance:   22,
+			landing:    Waypoint{X: 2, Y: 1, Altitude: 90, Distance: 22},
+		},
+		{
+			name:  "Terrain_Ignores_Elevations_Outside_The_Estate",
+			width: 2,
+			elevations: []Elevation{
+				{X: 3, Y: 1, Elevation: 50},
+				{X: 1, Y: 1, Elevation: 0},
+			},
+			distance: 12,
+			landing:  Waypoint{X: 2, Y: 1, Distance: 12},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := New(Options{Width: tc.width, Length: 1, Trees: tc.trees, BaseElevation: tc.base, Elevations: tc.elevations})
+			assert.Equal(t, tc.distance, plan.Distance())
+			assert.Equal(t, tc.landing, plan.Rest(1000))
+			assert.Equal(t, tc.base, plan.waypoint(0).Altitude)
+
+			route := plan.Route()
+			assert.Equal(t, tc.distance, route[len(route)-1].Distance)
+		})
+	}
+}
+
+func TestTerrainLandPastATree(t *testing.T) {
+	// Landing costs 2, 12, 22, 72, 62 and 72 along the row: up the rise
+	// past the tree the drone lands lower above the ground than on it.
+	plan := New(Options{
+		Width:      6,
+		Length:     1,
+		Trees:      []Tree{{X: 4, Y: 1, Height: 20}},
+		Elevations: []Elevation{{X: 5, Y: 1, Elevation: 20}, {X: 6, Y: 1, Elevation: 20}},
+	})
+
+	assert.Equal(t, Waypoint{X: 5, Y: 1, Altitude: 20, Distance: 62}, plan.Rest(65))
+	assert.Equal(t, Waypoint{X: 3, Y: 1, Distance: 22}, plan.Rest(61))
+
+	// The battery lands before the tree, and the next one carries the
+	// drone over it to land up the rise.
+	flight, err := plan.Fly(Drone{CruiseSpeed: 10, ClimbRate: 1, DescentRate: 1, Endurance: 45})
+	assert.NoError(t, err)
+	assert.Equal(t, 76, flight.Distance)
+	assert.Equal(t, 2, flight.Swaps)
+}
+
+func TestFlyDownhill(t *testing.T) {
+	plan := New(Options{Width: 2, Length: 1, BaseElevation: 100, Elevations: []Elevation{{X: 2, Y: 1, Elevation: 90}}})
+
+	flight, err := plan.Fly(Drone{CruiseSpeed: 10, ClimbRate: 1, DescentRate: 2, Endurance: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, Flight{Distance: 22, Duration: 1 + 1 + 5.5}, flight)
+}
+
+func TestTerrainMatchesPlotByPlotFlight(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		width := random.Intn(8) + 1
+		length := random.Intn(8) + 1
+		base := random.Intn(100)
+		trees := randomTrees(random, width, length, random.Intn(width*length/4+1))
+		elevations := make([]Elevation, random.Intn(width*length/2+1))
+		for j := range elevations {
+			elevations[j] = Elevation{X: random.Intn(width) + 1, Y: random.Intn(length) + 1, Elevation: base + random.Intn(41) - 20}
+		}
+
+		plan := New(Options{Width: width, Length: length, Trees: trees, BaseElevation: base, Elevations: elevations})
+
+		// Later elevations of the same plot win, like in the plan.
+		grounds := make(map[Plot]int)
+		for _, elevation := range elevations {
+			grounds[Plot{X: elevation.X, Y: elevation.Y}] = elevation.Elevation
+		}
+		heights := make(map[Plot]int)
+		for _, tree := range trees {
+			heights[Plot{X: tree.X, Y: tree.Y}] = tree.Height
+		}
+		ground := func(position int) int {
+			if g, ok := grounds[plan.path.plot(position)]; ok {
+				return g
+			}
+			return base
+		}
+		altitude := func(position int) int {
+			return ground(position) + heights[plan.path.plot(position)] + DefaultClearance
+		}
+
+		last := plan.path.len() - 1
+		distance := altitude(0) - ground(0) + altitude(last) - ground(last)
+		for position := 1; position <= last; position++ {
+			distance += PlotSize + abs(altitude(position)-altitude(position-1))
+		}
+		assert.Equal(t, distance, plan.Distance())
+
+		route := plan.Route()
+		assert.Equal(t, distance, route[len(route)-1].Distance)
+		assert.Equal(t, ground(0), route[0].Altitude)
+		assert.Equal(t, ground(last), route[len(route)-1].Altitude)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	points := []Elevation{
+		{X: 1, Y: 1, Elevation: 10},
+		{X: 3, Y: 1, Elevation: 20},
+	}
+
+	assert.Equal(t, []Elevation{
+		{X: 1, Y: 1, Elevation: 10},
+		{X: 2, Y: 1, Elevation: 15},
+		{X: 3, Y: 1, Elevation: 20},
+		{X: 1, Y: 2, Elevation: 12},
+		{X: 2, Y: 2, Elevation: 15},
+		{X: 3, Y: 2, Elevation: 18},
+	}, Interpolate(3, 2, points))
+	assert.Nil(t, Interpolate(3, 2, nil))
+}
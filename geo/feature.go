@@ -0,0 +1,171 @@
+package geo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MIMEGeoJSON is the media type of a GeoJSON document.
+	MIMEGeoJSON = "application/geo+json"
+	// MIMEKML is the media type of a KML document.
+	MIMEKML = "application/vnd.google-earth.kml+xml"
+)
+
+// Feature is a named point or line with properties. Exactly one of Point
+// and Line is set.
+type Feature struct {
+	Name       string
+	Properties map[string]interface{}
+	Point      *Coordinate
+	Line       []Coordinate
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// GeoJSON writes the features out as a GeoJSON feature collection.
+func GeoJSON(features []Feature) ([]byte, error) {
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(features)),
+	}
+
+	for _, feature := range features {
+		properties := map[string]interface{}{"name": feature.Name}
+		for key, value := range feature.Properties {
+			properties[key] = value
+		}
+
+		geometry := geoJSONGeometry{Type: "Point"}
+		if feature.Point != nil {
+			geometry.Coordinates = geoJSONPosition(*feature.Point)
+		} else {
+			positions := make([][3]float64, 0, len(feature.Line))
+			for _, coordinate := range feature.Line {
+				positions = append(positions, geoJSONPosition(coordinate))
+			}
+			geometry.Type = "LineString"
+			geometry.Coordinates = positions
+		}
+
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geometry,
+			Properties: properties,
+		})
+	}
+
+	return json.Marshal(collection)
+}
+
+func geoJSONPosition(coordinate Coordinate) [3]float64 {
+	return [3]float64{coordinate.Longitude, coordinate.Latitude, coordinate.Altitude}
+}
+
+type kmlDocument struct {
+	XMLName  xml.Name `xml:"kml"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Document struct {
+		Name       string         `xml:"name"`
+		Placemarks []kmlPlacemark `xml:"Placemark"`
+	} `xml:"Document"`
+}
+
+type kmlPlacemark struct {
+	Name         string       `xml:"name"`
+	ExtendedData *kmlData     `xml:"ExtendedData,omitempty"`
+	Point        *kmlGeometry `xml:"Point,omitempty"`
+	LineString   *kmlGeometry `xml:"LineString,omitempty"`
+}
+
+type kmlData struct {
+	Data []kmlDataValue `xml:"Data"`
+}
+
+type kmlDataValue struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlGeometry struct {
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+// KML writes the features out as a KML document with the given name.
+// Altitudes are relative to the ground.
+func KML(name string, features []Feature) ([]byte, error) {
+	document := kmlDocument{Xmlns: "http://www.opengis.net/kml/2.2"}
+	document.Document.Name = name
+
+	for _, feature := range features {
+		placemark := kmlPlacemark{Name: feature.Name}
+
+		if len(feature.Properties) > 0 {
+			keys := make([]string, 0, len(feature.Properties))
+			for key := range feature.Properties {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			placemark.ExtendedData = &kmlData{}
+			for _, key := range keys {
+				placemark.ExtendedData.Data = append(placemark.ExtendedData.Data, kmlDataValue{
+					Name:  key,
+					Value: fmt.Sprint(feature.Properties[key]),
+				})
+			}
+		}
+
+		if feature.Point != nil {
+			placemark.Point = &kmlGeometry{
+				AltitudeMode: "relativeToGround",
+				Coordinates:  kmlCoordinate(*feature.Point),
+			}
+		} else {
+			coordinates := make([]string, 0, len(feature.Line))
+			for _, coordinate := range feature.Line {
+				coordinates = append(coordinates, kmlCoordinate(coordinate))
+			}
+			placemark.LineString = &kmlGeometry{
+				AltitudeMode: "relativeToGround",
+				Coordinates:  strings.Join(coordinates, " "),
+			}
+		}
+
+		document.Document.Placemarks = append(document.Document.Placemarks, placemark)
+	}
+
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+func kmlCoordinate(coordinate Coordinate) string {
+	return strings.Join([]string{
+		strconv.FormatFloat(coordinate.Longitude, 'f', -1, 64),
+		strconv.FormatFloat(coordinate.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(coordinate.Altitude, 'f', -1, 64),
+	}, ",")
+}
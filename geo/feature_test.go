@@ -0,0 +1,77 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var features = []Feature{
+	{
+		Name:       "Tree",
+		Properties: map[string]interface{}{"height": 5, "x": 1},
+		Point:      &Coordinate{Latitude: -1.5, Longitude: 102.25, Altitude: 5},
+	},
+	{
+		Name: "Path",
+		Line: []Coordinate{
+			{Latitude: -1.5, Longitude: 102.25, Altitude: 1},
+			{Latitude: -1.5, Longitude: 102.5, Altitude: 6},
+		},
+	},
+}
+
+func TestGeoJSON(t *testing.T) {
+	body, err := GeoJSON(features)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [
+			{
+				"type": "Feature",
+				"geometry": {"type": "Point", "coordinates": [102.25, -1.5, 5]},
+				"properties": {"name": "Tree", "height": 5, "x": 1}
+			},
+			{
+				"type": "Feature",
+				"geometry": {"type": "LineString", "coordinates": [[102.25, -1.5, 1], [102.5, -1.5, 6]]},
+				"properties": {"name": "Path"}
+			}
+		]
+	}`, string(body))
+}
+
+func TestKML(t *testing.T) {
+	body, err := KML("Estate", features)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Estate</name>
+    <Placemark>
+      <name>Tree</name>
+      <ExtendedData>
+        <Data name="height">
+          <value>5</value>
+        </Data>
+        <Data name="x">
+          <value>1</value>
+        </Data>
+      </ExtendedData>
+      <Point>
+        <altitudeMode>relativeToGround</altitudeMode>
+        <coordinates>102.25,-1.5,5</coordinates>
+      </Point>
+    </Placemark>
+    <Placemark>
+      <name>Path</name>
+      <LineString>
+        <altitudeMode>relativeToGround</altitudeMode>
+        <coordinates>102.25,-1.5,1 102.5,-1.5,6</coordinates>
+      </LineString>
+    </Placemark>
+  </Document>
+</kml>`, string(body))
+}
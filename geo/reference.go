@@ -0,0 +1,67 @@
+// Package geo places estate plots on the globe and writes them out as KML
+// and GeoJSON.
+package geo
+
+import "math"
+
+// earthRadius is the mean radius of the earth in metres.
+const earthRadius = 6371008.8
+
+// Coordinate is a position on the globe. Altitude is in metres above the
+// ground.
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+}
+
+// Reference ties an estate grid to the globe. Latitude and Longitude give
+// the outer corner of plot (1, 1). Bearing is the compass direction in
+// degrees the rows are stacked towards, from row 1 to row 2. The plots of
+// a row run 90 degrees clockwise from it, so with a bearing of 0 the rows
+// run from west to east and are stacked towards the north.
+type Reference struct {
+	Latitude  float64
+	Longitude float64
+	Bearing   float64
+}
+
+// Locate returns the position of a point that lies along metres from the
+// estate corner in the direction of the rows and across metres in the
+// direction the rows are stacked.
+func (r Reference) Locate(along, across, altitude float64) Coordinate {
+	bearing := radians(r.Bearing)
+	east := along*math.Cos(bearing) + across*math.Sin(bearing)
+	north := across*math.Cos(bearing) - along*math.Sin(bearing)
+
+	distance := math.Hypot(east, north) / earthRadius
+	direction := math.Atan2(east, north)
+	lat := radians(r.Latitude)
+	lon := radians(r.Longitude)
+
+	destinationLat := math.Asin(math.Sin(lat)*math.Cos(distance) + math.Cos(lat)*math.Sin(distance)*math.Cos(direction))
+	destinationLon := lon + math.Atan2(
+		math.Sin(direction)*math.Sin(distance)*math.Cos(lat),
+		math.Cos(distance)-math.Sin(lat)*math.Sin(destinationLat),
+	)
+
+	return Coordinate{
+		Latitude:  degrees(destinationLat),
+		Longitude: math.Mod(degrees(destinationLon)+540, 360) - 180,
+		Altitude:  altitude,
+	}
+}
+
+// Plot returns the position of the centre of plot (x, y) when each plot
+// is size metres wide.
+func (r Reference) Plot(x, y int, size, altitude float64) Coordinate {
+	return r.Locate((float64(x)-0.5)*size, (float64(y)-0.5)*size, altitude)
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
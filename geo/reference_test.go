@@ -0,0 +1,57 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		reference  Reference
+		along      float64
+		across     float64
+		coordinate Coordinate
+	}{
+		{
+			name:       "Locate_Corner",
+			reference:  Reference{Latitude: -1.5, Longitude: 102.1},
+			coordinate: Coordinate{Latitude: -1.5, Longitude: 102.1},
+		},
+		{
+			name:       "Locate_Rows_Stacked_North",
+			reference:  Reference{Latitude: 0, Longitude: 100},
+			along:      1000,
+			across:     2000,
+			coordinate: Coordinate{Latitude: 0.017986, Longitude: 100.008993},
+		},
+		{
+			name:       "Locate_Rows_Stacked_East",
+			reference:  Reference{Latitude: 0, Longitude: 100, Bearing: 90},
+			along:      1000,
+			across:     2000,
+			coordinate: Coordinate{Latitude: -0.008993, Longitude: 100.017986},
+		},
+		{
+			name:       "Locate_Across_The_Antimeridian",
+			reference:  Reference{Latitude: 0, Longitude: 179.999},
+			along:      1000,
+			coordinate: Coordinate{Latitude: 0, Longitude: -179.992007},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coordinate := tc.reference.Locate(tc.along, tc.across, 0)
+			assert.InDelta(t, tc.coordinate.Latitude, coordinate.Latitude, 1e-6)
+			assert.InDelta(t, tc.coordinate.Longitude, coordinate.Longitude, 1e-6)
+		})
+	}
+}
+
+func TestPlot(t *testing.T) {
+	reference := Reference{Latitude: 0, Longitude: 100}
+
+	assert.Equal(t, reference.Locate(15, 25, 3), reference.Plot(2, 3, 10, 3))
+}
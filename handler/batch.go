@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+const (
+	// maxBatchEstates is the most estates a batch may plan.
+	maxBatchEstates = 100
+	// batchWorkers is the most estates of a batch planned at the same time.
+	batchWorkers = 8
+)
+
+// batchEstateIds checks the estate ids of a batch.
+func batchEstateIds(ids []string) error {
+	if len(ids) == 0 {
+		return invalidField("estate_ids", "Invalid Estate Ids")
+	}
+	if len(ids) > maxBatchEstates {
+		return limitExceeded("estate_ids", fmt.Sprintf("Batch has more than %d estates", maxBatchEstates), maxBatchEstates)
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !validId(id) {
+			return invalidField("estate_ids", "Invalid Estate Ids")
+		}
+		if seen[id] {
+			return invalidField("estate_ids", "Duplicate estate id")
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// batchDronePlans plans the estates of a batch over a pool of workers. The
+// trees of all the estates are loaded with one query up front, the rest of
+// each layout by the worker planning the estate. An estate that cannot be
+// planned gets its plan's error; a repository error fails the batch.
+func (s *Server) batchDronePlans(ctx context.Context, ids []string) ([]generated.BatchDronePlan, error) {
+	trees, err := s.Repository.GetTreesByEstateIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	estateTrees := make(map[string][]repository.EstateTree, len(ids))
+	for _, tree := range trees {
+		estateTrees[tree.EstateId] = append(estateTrees[tree.EstateId], tree)
+	}
+
+	plans := make([]generated.BatchDronePlan, len(ids))
+	errs := make([]error, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(batchWorkers, len(ids)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				plans[i], errs[i] = s.batchDronePlan(ctx, ids[i], estateTrees[ids[i]])
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return plans, nil
+}
+
+// batchDronePlan plans one estate of a batch with the given trees.
+func (s *Server) batchDronePlan(ctx context.Context, id string, trees []repository.EstateTree) (plan generated.BatchDronePlan, err error) {
+	plan.EstateId = id
+
+	estate, err := s.Repository.GetEstateById(ctx, id)
+	if err == sql.ErrNoRows {
+		message := "Estate not found"
+		plan.Error = &message
+		return plan, nil
+	}
+	if err != nil {
+		return
+	}
+
+	layout, err := s.loadEstateFeatures(ctx, id)
+	if err != nil {
+		return
+	}
+	layout.trees = trees
+
+	dronePlan := newDronePlan(estate, layout, droneplan.DefaultClearance)
+	if !dronePlan.Reachable() {
+		message := "No-fly zones make the estate unreachable"
+		plan.Error = &message
+		return plan, nil
+	}
+
+	distance, skipped := dronePlan.Distance(), dronePlan.SkippedPlots()
+	plan.Distance = &distance
+	plan.SkippedPlotCount = &skipped
+	return plan, nil
+}
+
+// batchTotals adds up the plans of a batch.
+func batchTotals(plans []generated.BatchDronePlan) generated.BatchDronePlanTotals {
+	totals := generated.BatchDronePlanTotals{Estates: len(plans)}
+	for _, plan := range plans {
+		if plan.Error != nil {
+			totals.Failed++
+			continue
+		}
+		totals.Planned++
+		totals.Distance += *plan.Distance
+		totals.SkippedPlotCount += *plan.SkippedPlotCount
+	}
+	return totals
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// newDrone validates a drone request and builds the drone to store.
+func newDrone(id string, req generated.CreateDroneRequest) (repository.Drone, error) {
+	model := strings.TrimSpace(req.Model)
+	if model == "" || len(model) > 100 {
+		return repository.Drone{}, invalidField("model", "Invalid Model")
+	}
+
+	if req.CruiseSpeed <= 0 {
+		return repository.Drone{}, invalidField("cruise_speed", "Invalid Cruise Speed")
+	}
+
+	if req.ClimbRate <= 0 {
+		return repository.Drone{}, invalidField("climb_rate", "Invalid Climb Rate")
+	}
+
+	if req.DescentRate <= 0 {
+		return repository.Drone{}, invalidField("descent_rate", "Invalid Descent Rate")
+	}
+
+	if req.Endurance <= 0 {
+		return repository.Drone{}, invalidField("endurance", "Invalid Endurance")
+	}
+
+	clearance := droneplan.DefaultClearance
+	if req.Clearance != nil {
+		if *req.Clearance < 1 || *req.Clearance > 100 {
+			return repository.Drone{}, invalidField("clearance", "Invalid Clearance")
+		}
+		clearance = *req.Clearance
+	}
+
+	return repository.Drone{
+		Id:          id,
+		Model:       model,
+		CruiseSpeed: req.CruiseSpeed,
+		ClimbRate:   req.ClimbRate,
+		DescentRate: req.DescentRate,
+		Endurance:   req.Endurance,
+		Clearance:   clearance,
+	}, nil
+}
+
+// droneResponse converts a stored drone to its API representation.
+func droneResponse(drone repository.Drone) generated.Drone {
+	return generated.Drone{
+		Id:          drone.Id,
+		Model:       drone.Model,
+		CruiseSpeed: drone.CruiseSpeed,
+		ClimbRate:   drone.ClimbRate,
+		DescentRate: drone.DescentRate,
+		Endurance:   drone.Endurance,
+		Clearance:   drone.Clearance,
+	}
+}
+
+// planDrone returns the flight characteristics of a stored drone.
+func planDrone(drone repository.Drone) droneplan.Drone {
+	return droneplan.Drone{
+		CruiseSpeed: drone.CruiseSpeed,
+		ClimbRate:   drone.ClimbRate,
+		DescentRate: drone.DescentRate,
+		Endurance:   drone.Endurance,
+	}
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+const (
+	// maxDrawnPlots is the most plots an estate may have for its drone plan
+	// to be drawn.
+	maxDrawnPlots = 250000
+	// defaultInspectionDescent and maxInspectionDescent are the metres the
+	// drone descends over a tree flagged for inspection.
+	defaultInspectionDescent = 5
+	maxInspectionDescent     = 100
+	// defaultInspectionHover and maxInspectionHover are the seconds the
+	// drone hovers over a tree flagged for inspection.
+	defaultInspectionHover = 10
+	maxInspectionHover     = 600
+	// maxCeiling is the highest max_altitude in metres an estate may set.
+	maxCeiling = 1000
+	// maxSkippedAboveCeiling is the most plots above the ceiling a drone
+	// plan may skip, each one an area the drone is routed around.
+	maxSkippedAboveCeiling = 100
+)
+
+// estateLayout is everything on an estate the drone plan has to keep clear
+// of.
+type estateLayout struct {
+	trees      []repository.EstateTree
+	obstacles  []repository.Obstacle
+	noFlyZones []repository.NoFlyZone
+	elevations []repository.PlotElevation
+	// region is the part of the estate to survey, or nil for all of it.
+	region *droneplan.Area
+}
+
+// loadEstateLayout loads the trees, obstacles, no-fly zones and ground
+// elevation of an estate.
+func (s *Server) loadEstateLayout(ctx context.Context, id string) (layout estateLayout, err error) {
+	layout, err = s.loadEstateFeatures(ctx, id)
+	if err != nil {
+		return
+	}
+
+	layout.trees, err = s.Repository.GetTreesByEstateId(ctx, id)
+	return
+}
+
+// loadRegionLayout loads the layout of the estate for a survey of one of
+// its regions flown with any of the given patterns. Only the trees under
+// the flight are loaded: the trees of the region and of the plots crossed
+// to fly there and back.
+func (s *Server) loadRegionLayout(ctx context.Context, estate repository.Estate, region droneplan.Area, patterns []droneplan.Pattern) (layout estateLayout, err error) {
+	layout, err = s.loadEstateFeatures(ctx, estate.Id)
+	if err != nil {
+		return
+	}
+	layout.region = &region
+
+	// The flight does not depend on the trees, only its altitudes do.
+	opts := dronePlanOptions(estate, layout, droneplan.DefaultClearance)
+	var bounds droneplan.Area
+	for i, pattern := range patterns {
+		opts.Pattern = pattern
+		plan := droneplan.New(opts)
+		if !plan.Reachable() {
+			return
+		}
+
+		flight := plan.Bounds()
+		if i == 0 {
+			bounds = flight
+			continue
+		}
+		bounds.From.X = min(bounds.From.X, flight.From.X)
+		bounds.From.Y = min(bounds.From.Y, flight.From.Y)
+		bounds.To.X = max(bounds.To.X, flight.To.X)
+		bounds.To.Y = max(bounds.To.Y, flight.To.Y)
+	}
+
+	layout.trees, err = s.Repository.GetTreesInArea(ctx, estate.Id, repository.Area{
+		FromX: bounds.From.X,
+		FromY: bounds.From.Y,
+		ToX:   bounds.To.X,
+		ToY:   bounds.To.Y,
+	})
+	return
+}
+
+// loadEstateFeatures loads everything of an estate the drone plan has to
+// keep clear of but the trees.
+func (s *Server) loadEstateFeatures(ctx context.Context, id string) (layout estateLayout, err error) {
+	layout.obstacles, err = s.Repository.GetObstaclesByEstateId(ctx, id)
+	if err != nil {
+		return
+	}
+
+	layout.noFlyZones, err = s.Repository.GetNoFlyZonesByEstateId(ctx, id)
+	if err != nil {
+		return
+	}
+
+	layout.elevations, err = s.Repository.GetElevationsByEstateId(ctx, id)
+	return
+}
+
+// newDronePlan builds the drone plan of an estate, keeping the given
+// clearance above its ground, trees and obstacles and clear of its no-fly
+// zones.
+func newDronePlan(estate repository.Estate, layout estateLayout, clearance int) *droneplan.Plan {
+	return droneplan.New(dronePlanOptions(estate, layout, clearance))
+}
+
+// dronePlanOptions returns the options newDronePlan builds the drone plan
+// of an estate with.
+func dronePlanOptions(estate repository.Estate, layout estateLayout, clearance int) droneplan.Options {
+	planTrees := make([]droneplan.Tree, 0, len(layout.trees))
+	for _, tree := range layout.trees {
+		planTrees = append(planTrees, droneplan.Tree{
+			X:       tree.X,
+			Y:       tree.Y,
+			Height:  tree.Height,
+			Inspect: tree.NeedsInspection,
+		})
+	}
+
+	planObstacles := make([]droneplan.Obstacle, 0, len(layout.obstacles))
+	for _, obstacle := range layout.obstacles {
+		planObstacles = append(planObstacles, droneplan.Obstacle{
+			X:      obstacle.X,
+			Y:      obstacle.Y,
+			Height: obstacle.Height,
+		})
+	}
+
+	noFlyZones := make([]droneplan.Area, 0, len(layout.noFlyZones))
+	for _, zone := range layout.noFlyZones {
+		noFlyZones = append(noFlyZones, droneplan.Area{
+			From: droneplan.Plot{X: zone.FromX, Y: zone.FromY},
+			To:   droneplan.Plot{X: zone.ToX, Y: zone.ToY},
+		})
+	}
+
+	elevations := make([]droneplan.Elevation, 0, len(layout.elevations))
+	for _, elevation := range layout.elevations {
+		elevations = append(elevations, droneplan.Elevation{
+			X:         elevation.X,
+			Y:         elevation.Y,
+			Elevation: elevation.Elevation,
+		})
+	}
+
+	maxAltitude := 0
+	if estate.MaxAltitude != nil {
+		maxAltitude = *estate.MaxAltitude
+	}
+
+	return droneplan.Options{
+		Width:         estate.Width,
+		Length:        estate.Length,
+		Trees:         planTrees,
+		Obstacles:     planObstacles,
+		Clearance:     clearance,
+		BaseElevation: estate.Elevation,
+		Elevations:    elevations,
+		NoFlyZones:    noFlyZones,
+		Region:        layout.region,
+		MaxAltitude:   maxAltitude,
+	}
+}
+
+// dronePlanCeiling lists the trees and obstacles of the layout that force
+// the drone above the ceiling of the estate, or returns nil when the estate
+// has none.
+func dronePlanCeiling(estate repository.Estate, layout estateLayout, plan *droneplan.Plan, skip bool) *generated.DronePlanCeiling {
+	if estate.MaxAltitude == nil {
+		return nil
+	}
+
+	ceiling := &generated.DronePlanCeiling{
+		MaxAltitude: *estate.MaxAltitude,
+		Skipped:     skip,
+		TreeIds:     []string{},
+		ObstacleIds: []string{},
+	}
+	plots := plan.AboveCeiling()
+	if skip {
+		plots = plan.SkippedAboveCeiling()
+	}
+	above := make(map[droneplan.Plot]bool, len(plots))
+	for _, plot := range plots {
+		above[plot] = true
+	}
+
+	for _, tree := range layout.trees {
+		if above[droneplan.Plot{X: tree.X, Y: tree.Y}] {
+			ceiling.TreeIds = append(ceiling.TreeIds, tree.Id)
+		}
+	}
+	for _, obstacle := range layout.obstacles {
+		if above[droneplan.Plot{X: obstacle.X, Y: obstacle.Y}] {
+			ceiling.ObstacleIds = append(ceiling.ObstacleIds, obstacle.Id)
+		}
+	}
+	return ceiling
+}
+
+// dronePlanInspection returns the metres the drone descends and the seconds
+// it hovers over every tree flagged for inspection.
+func dronePlanInspection(params generated.GetEstateIdDronePlanParams) (descent int, hover float64, err error) {
+	descent, hover = defaultInspectionDescent, defaultInspectionHover
+	if params.InspectionDescent != nil {
+		descent = *params.InspectionDescent
+		if descent < 1 || descent > maxInspectionDescent {
+			return 0, 0, invalidParameter("inspection_descent", "Invalid Inspection Descent")
+		}
+	}
+	if params.InspectionHover != nil {
+		hover = *params.InspectionHover
+		if hover < 0 || hover > maxInspectionHover {
+			return 0, 0, invalidParameter("inspection_hover", "Invalid Inspection Hover")
+		}
+	}
+	return descent, hover, nil
+}
+
+// dronePlanPatterns returns the patterns to build the drone plan with: the
+// one the request names, every pattern for auto, or the default one.
+func dronePlanPatterns(params generated.GetEstateIdDronePlanParams) ([]droneplan.Pattern, error) {
+	if params.Pattern == nil {
+		return []droneplan.Pattern{droneplan.PatternRows}, nil
+	}
+	if *params.Pattern == generated.Auto {
+		return droneplan.Patterns, nil
+	}
+
+	pattern := droneplan.Pattern(*params.Pattern)
+	if !pattern.Valid() {
+		return nil, invalidParameter("pattern", "Invalid Pattern")
+	}
+	return []droneplan.Pattern{pattern}, nil
+}
+
+// dronePlanRegion returns the region of the estate the drone plan surveys,
+// or nil when the request asks for the whole estate.
+func dronePlanRegion(estate repository.Estate, params generated.GetEstateIdDronePlanParams) (*droneplan.Area, error) {
+	corners := []*int{params.FromX, params.FromY, params.ToX, params.ToY}
+	given := 0
+	for _, corner := range corners {
+		if corner != nil {
+			given++
+		}
+	}
+	switch given {
+	case 0:
+		return nil, nil
+	case len(corners):
+	default:
+		return nil, invalidParameter("from_x", "from_x, from_y, to_x and to_y go together")
+	}
+
+	region := droneplan.Area{
+		From: droneplan.Plot{X: *params.FromX, Y: *params.FromY},
+		To:   droneplan.Plot{X: *params.ToX, Y: *params.ToY},
+	}
+	for _, corner := range []droneplan.Plot{region.From, region.To} {
+		if corner.X <= 0 || corner.Y <= 0 || corner.X > estate.Width || corner.Y > estate.Length {
+			return nil, badRequest(generated.OutOfBounds, "Region is outside the estate")
+		}
+	}
+	return &region, nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+const (
+	// minElevation and maxElevation bound the ground elevation of a plot
+	// in metres.
+	minElevation = -500
+	maxElevation = 9000
+	// maxSurveyPoints is the most survey points an interpolated upload may
+	// have.
+	maxSurveyPoints = 100
+	// maxElevationPlots is the most plots an upload may list, and the
+	// largest estate whose elevation may be interpolated.
+	maxElevationPlots = 250000
+)
+
+// newElevations validates an elevation upload for the estate and works out
+// its base elevation and the plots to store off the base.
+func newElevations(estate repository.Estate, req generated.PutElevationRequest) (base int, elevations []repository.PlotElevation, err error) {
+	if req.Mode != generated.Sparse && req.Mode != generated.Interpolate {
+		return 0, nil, invalidField("mode", "Invalid Mode")
+	}
+
+	if req.BaseElevation != nil && (*req.BaseElevation < minElevation || *req.BaseElevation > maxElevation) {
+		return 0, nil, invalidField("base_elevation", "Invalid Base Elevation")
+	}
+
+	seen := make(map[droneplan.Plot]bool, len(req.Points))
+	points := make([]droneplan.Elevation, 0, len(req.Points))
+	for _, point := range req.Points {
+		if point.X <= 0 || point.Y <= 0 || point.X > estate.Width || point.Y > estate.Length {
+			return 0, nil, badRequest(generated.OutOfBounds, "Elevation point is outside the estate").withField("points")
+		}
+		if point.Elevation < minElevation || point.Elevation > maxElevation {
+			return 0, nil, invalidField("points", "Invalid Elevation")
+		}
+		plot := droneplan.Plot{X: point.X, Y: point.Y}
+		if seen[plot] {
+			return 0, nil, invalidField("points", "Duplicate elevation point")
+		}
+		seen[plot] = true
+		points = append(points, droneplan.Elevation{X: point.X, Y: point.Y, Elevation: point.Elevation})
+	}
+
+	if req.Mode == generated.Sparse {
+		if len(points) > maxElevationPlots {
+			return 0, nil, limitExceeded("points", fmt.Sprintf("Upload lists more than %d plots", maxElevationPlots), maxElevationPlots)
+		}
+		if req.BaseElevation != nil {
+			base = *req.BaseElevation
+		}
+	} else {
+		if len(points) == 0 || len(points) > maxSurveyPoints {
+			return 0, nil, invalidField("points", fmt.Sprintf("Interpolation needs between 1 and %d survey points", maxSurveyPoints)).withDetail("max", maxSurveyPoints)
+		}
+		if estate.Width*estate.Length > maxElevationPlots {
+			return 0, nil, badRequest(generated.EstateTooLarge, "Estate is too large to interpolate").withDetail("max_plots", maxElevationPlots)
+		}
+		points = droneplan.Interpolate(estate.Width, estate.Length, points)
+		if req.BaseElevation != nil {
+			base = *req.BaseElevation
+		} else {
+			base = commonElevation(points)
+		}
+	}
+
+	for _, point := range points {
+		if point.Elevation == base {
+			continue
+		}
+		elevations = append(elevations, repository.PlotElevation{
+			EstateId:  estate.Id,
+			X:         point.X,
+			Y:         point.Y,
+			Elevation: point.Elevation,
+		})
+	}
+
+	return base, elevations, nil
+}
+
+// commonElevation returns the elevation most plots share, the lowest one
+// on a tie.
+func commonElevation(points []droneplan.Elevation) int {
+	counts := make(map[int]int)
+	for _, point := range points {
+		counts[point.Elevation]++
+	}
+
+	common := points[0].Elevation
+	for elevation, count := range counts {
+		if count > counts[common] || (count == counts[common] && elevation < common) {
+			common = elevation
+		}
+	}
+	return common
+}
+
+// elevationResponse converts the stored elevation of an estate to its API
+// representation.
+func elevationResponse(estate repository.Estate, elevations []repository.PlotElevation) generated.GetElevationResponse {
+	response := generated.GetElevationResponse{
+		BaseElevation: estate.Elevation,
+		Points:        []generated.PlotElevation{},
+	}
+	for _, elevation := range elevations {
+		response.Points = append(response.Points, generated.PlotElevation{
+			X:         elevation.X,
+			Y:         elevation.Y,
+			Elevation: elevation.Elevation,
+		})
+	}
+	return response
+}
@@ -2,10 +2,15 @@ package handler
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
 	"github.com/pebruwantoro/technical-test-sawitpro/generated"
 	"github.com/pebruwantoro/technical-test-sawitpro/repository"
 )
@@ -16,31 +21,30 @@ func (s *Server) PostEstate(c echo.Context) error {
 	ctx := c.Request().Context()
 
 	var req generated.CreateEstateRequest
-	var errResponse generated.ErrorResponse
 
 	if err := c.Bind(&req); err != nil {
-		errResponse.Message = "Invalid Request Body"
-		return c.JSON(http.StatusBadRequest, errResponse)
+		return invalidBody()
 	}
 
-	if req.Width <= 0 || req.Width > 50000 {
-		errResponse.Message = "Invalid Width"
-		return c.JSON(http.StatusBadRequest, errResponse)
+	estate := repository.Estate{
+		Id:          uuid.New().String(),
+		Width:       req.Width,
+		Length:      req.Length,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		MaxAltitude: req.MaxAltitude,
+	}
+	if req.Bearing != nil {
+		estate.Bearing = *req.Bearing
 	}
 
-	if req.Length <= 0 || req.Length > 50000 {
-		errResponse.Message = "Invalid Length"
-		return c.JSON(http.StatusBadRequest, errResponse)
+	if err := validateEstate(estate); err != nil {
+		return err
 	}
 
-	result, err := s.Repository.CreateEstate(ctx, repository.Estate{
-		Id:     uuid.New().String(),
-		Width:  req.Width,
-		Length: req.Length,
-	})
+	result, err := s.Repository.CreateEstate(ctx, estate)
 	if err != nil {
-		errResponse.Message = "Error to Create New Estate"
-		return c.JSON(http.StatusBadRequest, errResponse)
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, generated.CreateEstateResponse{
@@ -48,34 +52,152 @@ func (s *Server) PostEstate(c echo.Context) error {
 	})
 }
 
+// HANDLER FOR GET ALL ESTATES DATA
+// GET  /estate
+func (s *Server) GetEstate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	result, err := s.Repository.GetEstates(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := generated.GetEstatesResponse{
+		Estates: make([]generated.Estate, 0, len(result)),
+	}
+	for _, estate := range result {
+		response.Estates = append(response.Estates, estateResponse(estate))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR GET ESTATE DATA
+// GET  /estate/{id}
+func (s *Server) GetEstateId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	result, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, estateResponse(result))
+}
+
+// HANDLER FOR UPDATING ESTATE DATA
+// PATCH  /estate/{id}
+func (s *Server) PatchEstateId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.UpdateEstateRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	estate, err := patchEstate(estateData, req)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.Repository.UpdateEstate(ctx, estate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		if err == repository.ErrOutsideBounds {
+			return conflict(generated.EstateTooSmall, "Trees or obstacles lie outside the new size of the estate")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, estateResponse(result))
+}
+
+// HANDLER FOR DELETING ESTATE DATA
+// DELETE  /estate/{id}
+func (s *Server) DeleteEstateId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	err := s.Repository.DeleteEstate(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // HANDLER FOR CREATING TREE DATA
 // POST  /estate/{id}/tree
 func (s *Server) PostEstateIdTree(c echo.Context, id string) error {
 	ctx := c.Request().Context()
 
 	var req generated.CreateTreeRequest
-	var errResponse generated.ErrorResponse
 
 	if err := c.Bind(&req); err != nil {
-		errResponse.Message = err.Error()
-		return c.JSON(http.StatusBadRequest, errResponse)
+		return invalidBody()
+	}
+
+	if req.Height < 1 || req.Height > maxTreeHeight {
+		return badRequest(generated.InvalidHeight, "Invalid Height").withField("height")
+	}
+
+	if req.X <= 0 || req.Y <= 0 {
+		return badRequest(generated.OutOfBounds, "Tree is outside the estate")
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
 	}
 
-	if req.X < 0 || req.Y < 0 || req.Height < 0 || req.Height > 30 {
-		errResponse.Message = "Invalid payload X or Y position or Height"
-		return c.JSON(http.StatusBadRequest, errResponse)
+	if req.X > estateData.Width || req.Y > estateData.Length {
+		return badRequest(generated.OutOfBounds, "Tree is outside the estate").
+			withDetail("width", estateData.Width).
+			withDetail("length", estateData.Length)
 	}
 
 	result, err := s.Repository.CreateEstateTree(ctx, repository.EstateTree{
-		Id:       uuid.New().String(),
-		EstateId: id,
-		X:        req.X,
-		Y:        req.Y,
-		Height:   req.Height,
+		Id:              uuid.New().String(),
+		EstateId:        id,
+		X:               req.X,
+		Y:               req.Y,
+		Height:          req.Height,
+		NeedsInspection: req.NeedsInspection != nil && *req.NeedsInspection,
 	})
 	if err != nil {
-		errResponse.Message = err.Error()
-		return c.JSON(http.StatusBadRequest, errResponse)
+		if err == repository.ErrPlotOccupied {
+			return conflict(generated.PlotOccupied, "Plot already holds a tree or an obstacle")
+		}
+		if err == repository.ErrOutsideEstate {
+			return badRequest(generated.OutOfBounds, "Tree is outside the estate")
+		}
+
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, generated.CreateTreeResponse{
@@ -83,6 +205,104 @@ func (s *Server) PostEstateIdTree(c echo.Context, id string) error {
 	})
 }
 
+// HANDLER FOR UPDATING TREE INSPECTION DATA
+// PUT  /tree/{id}/inspection
+func (s *Server) PutTreeIdInspection(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.TreeInspection
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	if err := s.Repository.SetTreeInspection(ctx, id, req.NeedsInspection); err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.TreeNotFound, "Tree not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, req)
+}
+
+// HANDLER FOR RECORDING TREE MEASUREMENT DATA
+// POST  /tree/{id}/measurement
+func (s *Server) PostTreeIdMeasurement(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.RecordMeasurementRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	if req.Height < 1 || req.Height > maxTreeHeight {
+		return badRequest(generated.InvalidHeight, "Invalid Height").withField("height")
+	}
+
+	if req.MeasuredAt.IsZero() {
+		return invalidField("measured_at", "Invalid Measured At")
+	}
+
+	if req.MeasuredAt.After(time.Now()) {
+		return invalidField("measured_at", "Measured At must not be in the future")
+	}
+
+	if !validMeasurementSource(string(req.Source)) {
+		return invalidField("source", "Invalid Source")
+	}
+
+	result, err := s.Repository.RecordTreeMeasurement(ctx, repository.TreeMeasurement{
+		TreeId:     id,
+		Height:     req.Height,
+		MeasuredAt: req.MeasuredAt,
+		Source:     string(req.Source),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.TreeNotFound, "Tree not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, measurementResponse(result))
+}
+
+// HANDLER FOR GET TREE GROWTH DATA
+// GET  /tree/{id}/measurement
+func (s *Server) GetTreeIdMeasurement(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	treeData, err := s.Repository.GetTreeById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.TreeNotFound, "Tree not found")
+		}
+
+		return err
+	}
+
+	measurements, err := s.Repository.GetTreeMeasurements(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	response := generated.TreeGrowthResponse{
+		TreeId:       treeData.Id,
+		Height:       treeData.Height,
+		GrowthRate:   treeGrowthRate(measurements),
+		Measurements: make([]generated.TreeMeasurement, 0, len(measurements)),
+	}
+	for _, measurement := range measurements {
+		response.Measurements = append(response.Measurements, measurementResponse(measurement))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 // HANDLER FOR GET ESTATE STATISTICS DATA
 // GET  /estate/{id}/stats
 func (s *Server) GetEstateIdStats(c echo.Context, id string) error {
@@ -90,9 +310,7 @@ func (s *Server) GetEstateIdStats(c echo.Context, id string) error {
 
 	result, err := s.Repository.GetStatsByEstateId(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, generated.ErrorResponse{
-			Message: err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, generated.GetEstateStatsResponse{
@@ -103,41 +321,1133 @@ func (s *Server) GetEstateIdStats(c echo.Context, id string) error {
 	})
 }
 
+// HANDLER FOR GET ESTATE GROWTH DATA
+// GET  /estate/{id}/growth
+func (s *Server) GetEstateIdGrowth(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	_, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	result, err := s.Repository.GetEstateGrowth(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, generated.EstateGrowthResponse{
+		TreeCount:         result.TreeCount,
+		AverageGrowthRate: result.AverageRate,
+	})
+}
+
+// HANDLER FOR LISTING ESTATE TREES DATA
+// GET  /estate/{id}/trees
+func (s *Server) GetEstateIdTrees(c echo.Context, id string, params generated.GetEstateIdTreesParams) error {
+	ctx := c.Request().Context()
+
+	filter, err := treeFilter(id, params)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	// One tree past the page tells whether another page follows.
+	limit := filter.Limit
+	filter.Limit++
+	treesData, err := s.Repository.ListTrees(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	response := generated.GetTreesResponse{
+		Trees: make([]generated.Tree, 0, min(len(treesData), limit)),
+	}
+	if len(treesData) > limit {
+		treesData = treesData[:limit]
+		nextCursor := treeCursor(filter, treesData[limit-1])
+		response.NextCursor = &nextCursor
+	}
+	for _, tree := range treesData {
+		response.Trees = append(response.Trees, treeResponse(tree))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR EXPORTING ESTATE TREES DATA
+// GET  /estate/{id}/trees/export
+func (s *Server) GetEstateIdTreesExport(c echo.Context, id string, params generated.GetEstateIdTreesExportParams) error {
+	ctx := c.Request().Context()
+
+	if params.Format != "kml" && params.Format != "geojson" {
+		return invalidParameter("format", "Invalid Format")
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	reference := estateReference(estateData)
+	if reference == nil {
+		return badRequest(generated.EstateHasNoLocation, "Estate has no location")
+	}
+
+	treesData, err := s.Repository.GetTreesByEstateId(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return exportFeatures(c, string(params.Format), "Trees", id+"-trees", treeFeatures(*reference, treesData))
+}
+
 // HANDLER FOR GET ESTATE DRONE PLAN DATA
 // GET  /estate/{id}/drone-plan
-func (s *Server) GetEstateIdDronePlan(c echo.Context, id string) error {
+func (s *Server) GetEstateIdDronePlan(c echo.Context, id string, params generated.GetEstateIdDronePlanParams) error {
 	ctx := c.Request().Context()
 
+	if params.MaxDistance != nil && *params.MaxDistance <= 0 {
+		return invalidParameter("max_distance", "Invalid Max Distance")
+	}
+
+	if params.Drones != nil {
+		if *params.Drones <= 0 || *params.Drones > 100 {
+			return invalidParameter("drones", "Invalid Drones")
+		}
+
+		if params.MaxDistance != nil {
+			return invalidParameter("max_distance", "Max Distance is not supported for more than one drone")
+		}
+	}
+
+	if params.DroneId != nil && !validId(*params.DroneId) {
+		return invalidParameter("drone_id", "Invalid Drone Id")
+	}
+
+	if params.DroneId != nil && params.MaxDistance != nil {
+		return invalidParameter("max_distance", "Max Distance is not supported with a drone, its endurance is used instead")
+	}
+
+	format := generated.GetEstateIdDronePlanParamsFormat("json")
+	if params.Format != nil {
+		format = *params.Format
+	}
+
+	switch format {
+	case "json":
+	case "qgc", "kml", "geojson":
+		if params.MaxDistance != nil {
+			return invalidParameter("max_distance", "Max Distance is only supported for json format")
+		}
+
+		if params.Drones != nil {
+			return invalidParameter("drones", "Drones is only supported for json format")
+		}
+	default:
+		return invalidParameter("format", "Invalid Format")
+	}
+
+	patterns, err := dronePlanPatterns(params)
+	if err != nil {
+		return err
+	}
+
+	descent, hover, err := dronePlanInspection(params)
+	if err != nil {
+		return err
+	}
+
+	skipAboveCeiling := false
+	if params.Ceiling != nil {
+		switch *params.Ceiling {
+		case generated.Flag:
+		case generated.Skip:
+			skipAboveCeiling = true
+		default:
+			return invalidParameter("ceiling", "Invalid Ceiling")
+		}
+	}
+
 	estateData, err := s.Repository.GetEstateById(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return c.JSON(http.StatusNotFound, generated.ErrorResponse{
-				Message: "Estate not found",
-			})
+			return notFound(generated.EstateNotFound, "Estate not found")
 		}
 
-		return c.JSON(http.StatusBadRequest, generated.ErrorResponse{
-			Message: err.Error(),
-		})
+		return err
 	}
 
-	horizontalDistance := (estateData.Width-1)*estateData.Length + (estateData.Length-1)*estateData.Width
-	verticalDistance := 0
+	reference := estateReference(estateData)
+	if reference == nil && (format == "kml" || format == "geojson") {
+		return badRequest(generated.EstateHasNoLocation, "Estate has no location")
+	}
 
-	treesData, err := s.Repository.GetTreesByEstateId(ctx, id)
+	var droneData *repository.Drone
+	if params.DroneId != nil {
+		drone, err := s.Repository.GetDroneById(ctx, *params.DroneId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return notFound(generated.DroneNotFound, "Drone not found")
+			}
+
+			return err
+		}
+		droneData = &drone
+	}
+
+	region, err := dronePlanRegion(estateData, params)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, generated.ErrorResponse{
-			Message: err.Error(),
-		})
+		return err
+	}
+
+	// Skipping the plots above the ceiling steers the flight around trees
+	// anywhere on the estate, so a region is then laid over all of them.
+	var layout estateLayout
+	if region != nil && !(skipAboveCeiling && estateData.MaxAltitude != nil) {
+		layout, err = s.loadRegionLayout(ctx, estateData, *region, patterns)
+	} else {
+		layout, err = s.loadEstateLayout(ctx, id)
+		layout.region = region
+	}
+	if err != nil {
+		return err
+	}
+
+	clearance := droneplan.DefaultClearance
+	if droneData != nil {
+		clearance = droneData.Clearance
+	}
+	opts := dronePlanOptions(estateData, layout, clearance)
+	opts.SkipAboveCeiling = skipAboveCeiling
+	if skipAboveCeiling && droneplan.CountAboveCeiling(opts) > maxSkippedAboveCeiling {
+		return limitExceeded("ceiling", fmt.Sprintf("Estate has more than %d plots above the ceiling to skip", maxSkippedAboveCeiling), maxSkippedAboveCeiling)
+	}
+	var plan *droneplan.Plan
+	var candidates []droneplan.Candidate
+	if len(patterns) > 1 {
+		plan, candidates = droneplan.Choose(opts)
+	} else {
+		opts.Pattern = patterns[0]
+		plan = droneplan.New(opts)
+	}
+	if !plan.Reachable() {
+		message := "No-fly zones make the estate unreachable"
+		if region != nil {
+			message = "No-fly zones make the region unreachable"
+		}
+		return badRequest(generated.PlanUnreachable, message)
 	}
 
-	if len(treesData) > 0 {
-		for _, tree := range treesData {
-			verticalDistance += tree.Height
+	switch format {
+	case "qgc":
+		qgcPlan := plan.QGC(reference)
+		if droneData != nil {
+			qgcPlan.Mission.CruiseSpeed = droneData.CruiseSpeed
 		}
+
+		c.Response().Header().Set(echo.HeaderContentType, "application/vnd.qgroundcontrol.plan+json")
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.plan"`, id))
+		return c.JSON(http.StatusOK, qgcPlan)
+	case "kml", "geojson":
+		return exportFeatures(c, string(format), "Drone Plan", id, plan.Features(*reference))
 	}
 
-	return c.JSON(http.StatusOK, generated.GetDronePlanResponse{
-		Distance: horizontalDistance + verticalDistance,
-	})
+	response := generated.GetDronePlanResponse{
+		SkippedPlotCount: plan.SkippedPlots(),
+		Skipped:          skippedAreas(plan),
+		Ceiling:          dronePlanCeiling(estateData, layout, plan, skipAboveCeiling),
+	}
+	if params.Pattern != nil {
+		pattern := string(plan.Pattern())
+		response.Pattern = &pattern
+	}
+	var drone *droneplan.Drone
+	if droneData != nil {
+		planned := planDrone(*droneData)
+		drone = &planned
+	}
+	if inspection := plan.Inspect(descent, hover, drone); inspection.Stops > 0 {
+		response.Inspection = &generated.DronePlanInspection{
+			Stops:    inspection.Stops,
+			Distance: inspection.Distance,
+			Time:     inspection.Duration,
+		}
+	}
+	if candidates != nil {
+		response.Candidates = &[]generated.DronePlanCandidate{}
+		for _, candidate := range candidates {
+			*response.Candidates = append(*response.Candidates, generated.DronePlanCandidate{
+				Pattern:  string(candidate.Pattern),
+				Distance: candidate.Distance,
+			})
+		}
+	}
+
+	if params.Drones != nil {
+		sections := plan.Split(*params.Drones)
+		if sections == nil {
+			return badRequest(generated.PlanInfeasible, "Estate has fewer plots than drones").withField("drones")
+		}
+
+		response.Drones = &[]generated.DronePlanSection{}
+		for i, section := range sections {
+			responseSection := generated.DronePlanSection{
+				Drone:    i + 1,
+				Start:    generated.EstatePlot{X: section.Start.X, Y: section.Start.Y},
+				End:      generated.EstatePlot{X: section.End.X, Y: section.End.Y},
+				Distance: section.Distance,
+			}
+
+			if droneData != nil {
+				flight, err := plan.FlySection(section, planDrone(*droneData))
+				if err != nil {
+					return badRequest(generated.PlanInfeasible, "Drone endurance is too short to survey the estate")
+				}
+				responseSection.Distance = flight.Distance
+				responseSection.FlightTime = &flight.Duration
+				responseSection.BatterySwaps = &flight.Swaps
+			}
+
+			response.Distance += responseSection.Distance
+			*response.Drones = append(*response.Drones, responseSection)
+		}
+
+		if droneData != nil {
+			response.Clearance = &droneData.Clearance
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+
+	if droneData != nil {
+		flight, err := plan.Fly(planDrone(*droneData))
+		if err != nil {
+			return badRequest(generated.PlanInfeasible, "Drone endurance is too short to survey the estate")
+		}
+
+		response.Distance = flight.Distance
+		response.Clearance = &droneData.Clearance
+		response.FlightTime = &flight.Duration
+		response.BatterySwaps = &flight.Swaps
+		return c.JSON(http.StatusOK, response)
+	}
+
+	if params.MaxDistance != nil {
+		rest := plan.Rest(*params.MaxDistance)
+		response.Distance = rest.Distance
+		response.Rest = &generated.DronePlanRest{
+			X: rest.X,
+			Y: rest.Y,
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
+	response.Distance = plan.Distance()
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR GET ESTATE DRONE PLAN WAYPOINTS DATA
+// GET  /estate/{id}/drone-plan/waypoints
+func (s *Server) GetEstateIdDronePlanWaypoints(c echo.Context, id string, params generated.GetEstateIdDronePlanWaypointsParams) error {
+	ctx := c.Request().Context()
+
+	offset := 0
+	if params.Cursor != nil {
+		cursor, err := strconv.Atoi(*params.Cursor)
+		if err != nil || cursor < 0 {
+			return invalidParameter("cursor", "Invalid Cursor")
+		}
+		offset = cursor
+	}
+
+	limit := 100
+	if params.Limit != nil {
+		if *params.Limit <= 0 || *params.Limit > 1000 {
+			return invalidParameter("limit", "Invalid Limit")
+		}
+		limit = *params.Limit
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	layout, err := s.loadEstateLayout(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	plan := newDronePlan(estateData, layout, droneplan.DefaultClearance)
+	if !plan.Reachable() {
+		return badRequest(generated.PlanUnreachable, "No-fly zones make the estate unreachable")
+	}
+
+	response := generated.GetDronePlanWaypointsResponse{
+		Waypoints: []generated.DronePlanWaypoint{},
+	}
+	for _, waypoint := range plan.Waypoints(offset, limit) {
+		response.Waypoints = append(response.Waypoints, generated.DronePlanWaypoint{
+			X:        waypoint.X,
+			Y:        waypoint.Y,
+			Altitude: waypoint.Altitude,
+			Distance: waypoint.Distance,
+		})
+	}
+
+	if next := offset + len(response.Waypoints); next < plan.CountWaypoints() {
+		nextCursor := strconv.Itoa(next)
+		response.NextCursor = &nextCursor
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR DRAWING ESTATE DRONE PLAN DATA
+// GET  /estate/{id}/drone-plan.svg
+func (s *Server) GetEstateIdDronePlanSvg(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	if estateData.Width*estateData.Length > maxDrawnPlots {
+		return badRequest(generated.EstateTooLarge, "Estate is too large to draw").withDetail("max_plots", maxDrawnPlots)
+	}
+
+	layout, err := s.loadEstateLayout(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	plan := newDronePlan(estateData, layout, droneplan.DefaultClearance)
+	if !plan.Reachable() {
+		return badRequest(generated.PlanUnreachable, "No-fly zones make the estate unreachable")
+	}
+
+	return c.Blob(http.StatusOK, droneplan.MIMESVG, plan.SVG())
+}
+
+// HANDLER FOR SIMULATING ESTATE DRONE PLAN DATA
+// POST  /estate/{id}/drone-plan/simulate
+func (s *Server) PostEstateIdDronePlanSimulate(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.SimulateDronePlanRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	layout, err := s.loadEstateLayout(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	trees, err := simulateTrees(estateData, layout, req)
+	if err != nil {
+		return err
+	}
+
+	before := newDronePlan(estateData, layout, droneplan.DefaultClearance)
+	if !before.Reachable() {
+		return badRequest(generated.PlanUnreachable, "No-fly zones make the estate unreachable")
+	}
+
+	layout.trees = trees
+	after := newDronePlan(estateData, layout, droneplan.DefaultClearance)
+
+	return c.JSON(http.StatusOK, generated.SimulateDronePlanResponse{
+		OldDistance: before.Distance(),
+		NewDistance: after.Distance(),
+		Difference:  after.Distance() - before.Distance(),
+	})
+}
+
+// HANDLER FOR BATCH DRONE PLAN DATA
+// POST  /drone-plans/batch
+func (s *Server) PostDronePlansBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req generated.BatchDronePlanRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	if err := batchEstateIds(req.EstateIds); err != nil {
+		return err
+	}
+
+	plans, err := s.batchDronePlans(ctx, req.EstateIds)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, generated.BatchDronePlanResponse{
+		Plans:  plans,
+		Totals: batchTotals(plans),
+	})
+}
+
+// HANDLER FOR CREATING ESTATE OBSTACLE DATA
+// POST  /estate/{id}/obstacle
+func (s *Server) PostEstateIdObstacle(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.CreateObstacleRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	if req.X <= 0 || req.Y <= 0 {
+		return badRequest(generated.OutOfBounds, "Invalid X or Y position")
+	}
+
+	if req.Height < 1 || req.Height > maxObstacleHeight {
+		return invalidField("height", "Invalid Height")
+	}
+
+	if kind := strings.TrimSpace(req.Kind); kind == "" || len(kind) > 100 {
+		return invalidField("kind", "Invalid Kind")
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	if req.X > estateData.Width || req.Y > estateData.Length {
+		return badRequest(generated.OutOfBounds, "Obstacle is outside the estate")
+	}
+
+	result, err := s.Repository.CreateObstacle(ctx, repository.Obstacle{
+		Id:       uuid.New().String(),
+		EstateId: id,
+		X:        req.X,
+		Y:        req.Y,
+		Height:   req.Height,
+		Kind:     strings.TrimSpace(req.Kind),
+	})
+	if err != nil {
+		if err == repository.ErrPlotOccupied {
+			return conflict(generated.PlotOccupied, "Plot already holds a tree or an obstacle")
+		}
+		if err == repository.ErrOutsideEstate {
+			return badRequest(generated.OutOfBounds, "Obstacle is outside the estate")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, obstacleResponse(result))
+}
+
+// HANDLER FOR GET ESTATE OBSTACLES DATA
+// GET  /estate/{id}/obstacle
+func (s *Server) GetEstateIdObstacle(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	_, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	obstaclesData, err := s.Repository.GetObstaclesByEstateId(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	response := generated.GetObstaclesResponse{
+		Obstacles: []generated.Obstacle{},
+	}
+	for _, obstacle := range obstaclesData {
+		response.Obstacles = append(response.Obstacles, obstacleResponse(obstacle))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR DELETING OBSTACLE DATA
+// DELETE  /obstacle/{id}
+func (s *Server) DeleteObstacleId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	err := s.Repository.DeleteObstacle(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.ObstacleNotFound, "Obstacle not found")
+		}
+
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HANDLER FOR CREATING ESTATE NO-FLY ZONE DATA
+// POST  /estate/{id}/no-fly-zone
+func (s *Server) PostEstateIdNoFlyZone(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.CreateNoFlyZoneRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	if req.From.X <= 0 || req.From.Y <= 0 || req.To.X <= 0 || req.To.Y <= 0 {
+		return invalidField("", "Invalid No-Fly Zone")
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	zone := newNoFlyZone(uuid.New().String(), id, req)
+	if zone.ToX > estateData.Width || zone.ToY > estateData.Length {
+		return badRequest(generated.OutOfBounds, "No-Fly Zone is outside the estate")
+	}
+
+	zonesData, err := s.Repository.GetNoFlyZonesByEstateId(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(zonesData) >= maxNoFlyZones {
+		return limitExceeded("", fmt.Sprintf("Estate has %d no-fly zones already", maxNoFlyZones), maxNoFlyZones)
+	}
+
+	result, err := s.Repository.CreateNoFlyZone(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, noFlyZoneResponse(result))
+}
+
+// HANDLER FOR GET ESTATE NO-FLY ZONES DATA
+// GET  /estate/{id}/no-fly-zone
+func (s *Server) GetEstateIdNoFlyZone(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	_, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	zonesData, err := s.Repository.GetNoFlyZonesByEstateId(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	response := generated.GetNoFlyZonesResponse{
+		NoFlyZones: []generated.NoFlyZone{},
+	}
+	for _, zone := range zonesData {
+		response.NoFlyZones = append(response.NoFlyZones, noFlyZoneResponse(zone))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR DELETING NO-FLY ZONE DATA
+// DELETE  /no-fly-zone/{id}
+func (s *Server) DeleteNoFlyZoneId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	err := s.Repository.DeleteNoFlyZone(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.NoFlyZoneNotFound, "No-fly zone not found")
+		}
+
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HANDLER FOR UPLOADING ESTATE ELEVATION DATA
+// PUT  /estate/{id}/elevation
+func (s *Server) PutEstateIdElevation(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.PutElevationRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	base, elevations, err := newElevations(estateData, req)
+	if err != nil {
+		return err
+	}
+
+	err = s.Repository.ReplaceElevations(ctx, id, base, elevations)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, generated.PutElevationResponse{
+		BaseElevation: base,
+		PointCount:    len(elevations),
+	})
+}
+
+// HANDLER FOR GET ESTATE ELEVATION DATA
+// GET  /estate/{id}/elevation
+func (s *Server) GetEstateIdElevation(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	elevationsData, err := s.Repository.GetElevationsByEstateId(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, elevationResponse(estateData, elevationsData))
+}
+
+// HANDLER FOR CREATING DRONE DATA
+// POST  /drone
+func (s *Server) PostDrone(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req generated.CreateDroneRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	drone, err := newDrone(uuid.New().String(), req)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.Repository.CreateDrone(ctx, drone)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, droneResponse(result))
+}
+
+// HANDLER FOR GET DRONES DATA
+// GET  /drone
+func (s *Server) GetDrone(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	result, err := s.Repository.GetDrones(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := generated.GetDronesResponse{
+		Drones: make([]generated.Drone, 0, len(result)),
+	}
+	for _, drone := range result {
+		response.Drones = append(response.Drones, droneResponse(drone))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR GET DRONE DATA
+// GET  /drone/{id}
+func (s *Server) GetDroneId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	result, err := s.Repository.GetDroneById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.DroneNotFound, "Drone not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, droneResponse(result))
+}
+
+// HANDLER FOR UPDATING DRONE DATA
+// PUT  /drone/{id}
+func (s *Server) PutDroneId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.CreateDroneRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	drone, err := newDrone(id, req)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.Repository.UpdateDrone(ctx, drone)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.DroneNotFound, "Drone not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, droneResponse(result))
+}
+
+// HANDLER FOR DELETING DRONE DATA
+// DELETE  /drone/{id}
+func (s *Server) DeleteDroneId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	err := s.Repository.DeleteDrone(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.DroneNotFound, "Drone not found")
+		}
+		if err == repository.ErrDroneInUse {
+			return conflict(generated.DroneInUse, "Drone has missions")
+		}
+
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HANDLER FOR SCHEDULING ESTATE MISSION DATA
+// POST  /estate/{id}/mission
+func (s *Server) PostEstateIdMission(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.CreateMissionRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	if !validId(req.DroneId) {
+		return invalidField("drone_id", "Invalid Drone Id")
+	}
+
+	if req.ScheduledAt.IsZero() {
+		return invalidField("scheduled_at", "Invalid Scheduled At")
+	}
+
+	estateData, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	droneData, err := s.Repository.GetDroneById(ctx, req.DroneId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.DroneNotFound, "Drone not found")
+		}
+
+		return err
+	}
+
+	layout, err := s.loadEstateLayout(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	opts := dronePlanOptions(estateData, layout, droneData.Clearance)
+	plan := droneplan.New(opts)
+	if !plan.Reachable() {
+		return badRequest(generated.PlanUnreachable, "No-fly zones make the estate unreachable")
+	}
+
+	flight, err := plan.Fly(planDrone(droneData))
+	if err != nil {
+		return badRequest(generated.PlanInfeasible, "Drone endurance is too short to survey the estate")
+	}
+
+	result, err := s.Repository.CreateMission(ctx, repository.Mission{
+		Id:              uuid.New().String(),
+		EstateId:        id,
+		DroneId:         droneData.Id,
+		PlannedDistance: flight.Distance,
+		TreeCount:       len(layout.trees),
+		ScheduledAt:     req.ScheduledAt,
+		Status:          repository.MissionStatusPlanned,
+		LayoutDigest:    layoutDigest(opts),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, missionResponse(result))
+}
+
+// HANDLER FOR GET ESTATE MISSIONS DATA
+// GET  /estate/{id}/mission
+func (s *Server) GetEstateIdMission(c echo.Context, id string, params generated.GetEstateIdMissionParams) error {
+	ctx := c.Request().Context()
+
+	filter := repository.MissionFilter{
+		EstateId:      id,
+		ScheduledFrom: params.ScheduledFrom,
+		ScheduledTo:   params.ScheduledTo,
+	}
+
+	if params.Status != nil {
+		status := string(*params.Status)
+		if !validMissionStatus(status) {
+			return invalidParameter("status", "Invalid Status")
+		}
+		filter.Status = &status
+	}
+
+	if params.ScheduledFrom != nil && params.ScheduledTo != nil && !params.ScheduledFrom.Before(*params.ScheduledTo) {
+		return invalidParameter("scheduled_from", "Scheduled From must be before Scheduled To")
+	}
+
+	_, err := s.Repository.GetEstateById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.EstateNotFound, "Estate not found")
+		}
+
+		return err
+	}
+
+	result, err := s.Repository.GetMissions(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	response := generated.GetMissionsResponse{
+		Missions: make([]generated.Mission, 0, len(result)),
+	}
+	for _, mission := range result {
+		response.Missions = append(response.Missions, missionResponse(mission))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// HANDLER FOR GET MISSION DATA
+// GET  /mission/{id}
+func (s *Server) GetMissionId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	result, err := s.Repository.GetMissionById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.MissionNotFound, "Mission not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, missionResponse(result))
+}
+
+// HANDLER FOR UPDATING MISSION DATA
+// PATCH  /mission/{id}
+func (s *Server) PatchMissionId(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	var req generated.UpdateMissionRequest
+
+	if err := c.Bind(&req); err != nil {
+		return invalidBody()
+	}
+
+	status := string(req.Status)
+	if !validMissionStatus(status) {
+		return invalidField("status", "Invalid Status")
+	}
+
+	if req.ActualDistance != nil {
+		if *req.ActualDistance < 0 {
+			return invalidField("actual_distance", "Invalid Actual Distance")
+		}
+
+		if status != repository.MissionStatusCompleted && status != repository.MissionStatusAborted {
+			return invalidField("actual_distance", "Actual Distance can only be set on a completed or aborted mission")
+		}
+	}
+
+	mission, err := s.Repository.GetMissionById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.MissionNotFound, "Mission not found")
+		}
+
+		return err
+	}
+
+	if !canMoveMission(mission.Status, status) {
+		return conflict(generated.InvalidTransition, fmt.Sprintf("Mission cannot move from %s to %s", mission.Status, status))
+	}
+
+	from := mission.Status
+	mission.Status = status
+	if req.ActualDistance != nil {
+		mission.ActualDistance = req.ActualDistance
+	}
+
+	result, err := s.Repository.UpdateMission(ctx, mission, from)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.MissionNotFound, "Mission not found")
+		}
+
+		if err == repository.ErrMissionMoved {
+			return conflict(generated.InvalidTransition, fmt.Sprintf("Mission moved from %s meanwhile", from))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, missionResponse(result))
+}
+
+// HANDLER FOR UPLOADING MISSION TELEMETRY DATA
+// POST  /mission/{id}/telemetry
+func (s *Server) PostMissionIdTelemetry(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	samples, err := parseTelemetry(c.Request().Header.Get(echo.HeaderContentType), c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	mission, err := s.Repository.GetMissionById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.MissionNotFound, "Mission not found")
+		}
+
+		return err
+	}
+
+	plan, layoutChanged, err := s.missionPlan(ctx, mission)
+	if err != nil {
+		return err
+	}
+
+	err = s.Repository.ReplaceTelemetry(ctx, mission.Id, samples)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, telemetryReport(mission, plan, layoutChanged, samples))
+}
+
+// HANDLER FOR GET MISSION TELEMETRY REPORT DATA
+// GET  /mission/{id}/telemetry/report
+func (s *Server) GetMissionIdTelemetryReport(c echo.Context, id string) error {
+	ctx := c.Request().Context()
+
+	mission, err := s.Repository.GetMissionById(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound(generated.MissionNotFound, "Mission not found")
+		}
+
+		return err
+	}
+
+	samples, err := s.Repository.GetTelemetryByMissionId(ctx, mission.Id)
+	if err != nil {
+		return err
+	}
+
+	if len(samples) == 0 {
+		return notFound(generated.FlightLogNotFound, "Flight log not found")
+	}
+
+	plan, layoutChanged, err := s.missionPlan(ctx, mission)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, telemetryReport(mission, plan, layoutChanged, samples))
 }
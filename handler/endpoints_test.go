@@ -2,15 +2,20 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
 	"github.com/pebruwantoro/technical-test-sawitpro/generated"
 	"github.com/pebruwantoro/technical-test-sawitpro/repository"
 	"github.com/stretchr/testify/assert"
@@ -32,6 +37,7 @@ type testCase struct {
 	name       string
 	pathId     string
 	request    args
+	params     interface{}
 	response   interface{}
 	mockFunc   func()
 	statusCode int
@@ -49,6 +55,50 @@ func initialize(t *testing.T) func() {
 	return func() {}
 }
 
+// handle writes the error a handler returns as the server does.
+func handle(c echo.Context, err error) {
+	if err != nil {
+		HTTPErrorHandler(err, c)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func formatPtr(format string) *generated.GetEstateIdDronePlanParamsFormat {
+	f := generated.GetEstateIdDronePlanParamsFormat(format)
+	return &f
+}
+
+func ceilingPtr(ceiling string) *generated.GetEstateIdDronePlanParamsCeiling {
+	c := generated.GetEstateIdDronePlanParamsCeiling(ceiling)
+	return &c
+}
+
+func patternPtr(pattern string) *generated.GetEstateIdDronePlanParamsPattern {
+	p := generated.GetEstateIdDronePlanParamsPattern(pattern)
+	return &p
+}
+
+func sortPtr(sort string) *generated.GetEstateIdTreesParamsSort {
+	s := generated.GetEstateIdTreesParamsSort(sort)
+	return &s
+}
+
+func orderPtr(order string) *generated.GetEstateIdTreesParamsOrder {
+	o := generated.GetEstateIdTreesParamsOrder(order)
+	return &o
+}
+
 func TestPostEstate(t *testing.T) {
 	testCases := []testCase{
 		{
@@ -65,6 +115,66 @@ func TestPostEstate(t *testing.T) {
 			},
 			statusCode: http.StatusCreated,
 		},
+		{
+			name: "PostEstate_Success_With_Location",
+			request: args{
+				payload: `{ "length": 10, "width": 10, "latitude": -0.5071, "longitude": 101.4478, "bearing": 45 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().CreateEstate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input repository.Estate) (repository.Estate, error) {
+					assert.Equal(t, -0.5071, *input.Latitude)
+					assert.Equal(t, 101.4478, *input.Longitude)
+					assert.Equal(t, 45.0, input.Bearing)
+					return input, nil
+				})
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name: "PostEstate_Success_With_Max_Altitude",
+			request: args{
+				payload: `{ "length": 10, "width": 10, "max_altitude": 120 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().CreateEstate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input repository.Estate) (repository.Estate, error) {
+					assert.Equal(t, 120, *input.MaxAltitude)
+					return input, nil
+				})
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name: "PostEstate_Error_Max_Altitude_Out_Off_Range",
+			request: args{
+				payload: `{ "length": 10, "width": 10, "max_altitude": 0 }`,
+			},
+			mockFunc:   func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostEstate_Error_Latitude_Without_Longitude",
+			request: args{
+				payload: `{ "length": 10, "width": 10, "latitude": -0.5071 }`,
+			},
+			mockFunc:   func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostEstate_Error_Latitude_Out_Off_Range",
+			request: args{
+				payload: `{ "length": 10, "width": 10, "latitude": -91, "longitude": 101.4478 }`,
+			},
+			mockFunc:   func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostEstate_Error_Bearing_Out_Off_Range",
+			request: args{
+				payload: `{ "length": 10, "width": 10, "latitude": -0.5071, "longitude": 101.4478, "bearing": 360 }`,
+			},
+			mockFunc:   func() {},
+			statusCode: http.StatusBadRequest,
+		},
 		{
 			name: "PostEstate_Error_Width_Out_Off_Range",
 			request: args{
@@ -108,7 +218,7 @@ func TestPostEstate(t *testing.T) {
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rr := httptest.NewRecorder()
 			c := e.NewContext(req, rr)
-			_ = server.PostEstate(c)
+			handle(c, server.PostEstate(c))
 
 			var resp generated.CreateEstateResponse
 			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
@@ -117,75 +227,79 @@ func TestPostEstate(t *testing.T) {
 	}
 }
 
-func TestPostEstateIdTree(t *testing.T) {
+func TestGetEstate(t *testing.T) {
 	testCases := []testCase{
 		{
-			name:   "PostEstateIdTree_Success",
-			pathId: "uuid-1",
-			request: args{
-				payload: `{ "x": 10, "y": 10, "height": 10 }`,
-			},
+			name: "GetEstate_Success",
 			mockFunc: func() {
-				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{
-					Id:       "1",
-					EstateId: "uuid-1",
-					X:        10,
-					Y:        10,
-					Height:   10,
+				mockRepo.EXPECT().GetEstates(gomock.Any()).Return([]repository.Estate{
+					{Id: "uuid-1", Width: 10, Length: 5, Latitude: float64Ptr(-0.5071), Longitude: float64Ptr(101.4478), Bearing: 45, Elevation: 30, MaxAltitude: intPtr(150)},
+					{Id: "uuid-2", Width: 3, Length: 3},
 				}, nil)
 			},
-			statusCode: http.StatusCreated,
+			response: generated.GetEstatesResponse{
+				Estates: []generated.Estate{
+					{Id: "uuid-1", Width: 10, Length: 5, Latitude: float64Ptr(-0.5071), Longitude: float64Ptr(101.4478), Bearing: 45, Elevation: 30, MaxAltitude: intPtr(150)},
+					{Id: "uuid-2", Width: 3, Length: 3},
+				},
+			},
+			statusCode: http.StatusOK,
 		},
 		{
-			name:   "PostEstateIdTree_Error_X_Out_Off_Range",
-			pathId: "uuid-1",
-			request: args{
-				payload: `{ "x": -1, "y": 10, "height": 10 }`,
-			},
+			name: "GetEstate_Success_Without_Estates",
 			mockFunc: func() {
-				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{
-					Id:       "1",
-					EstateId: "uuid-1",
-					X:        -1,
-					Y:        10,
-					Height:   10,
-				}, errors.New("error x out of range"))
+				mockRepo.EXPECT().GetEstates(gomock.Any()).Return(nil, nil)
 			},
-			statusCode: http.StatusBadRequest,
+			response: generated.GetEstatesResponse{
+				Estates: []generated.Estate{},
+			},
+			statusCode: http.StatusOK,
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/estate", nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetEstate(c))
+
+			var resp generated.GetEstatesResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateId(t *testing.T) {
+	testCases := []testCase{
 		{
-			name:   "PostEstateIdTree_Error_Y_Out_Off_Range",
+			name:   "GetEstateId_Success",
 			pathId: "uuid-1",
-			request: args{
-				payload: `{ "x": 10, "y": -10, "height": 10 }`,
-			},
 			mockFunc: func() {
-				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{
-					Id:       "1",
-					EstateId: "uuid-1",
-					X:        10,
-					Y:        -10,
-					Height:   10,
-				}, errors.New("error y out of range"))
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id: "uuid-1", Width: 10, Length: 5, Bearing: 45, MaxAltitude: intPtr(150),
+				}, nil)
 			},
-			statusCode: http.StatusBadRequest,
+			response: generated.Estate{
+				Id: "uuid-1", Width: 10, Length: 5, Bearing: 45, MaxAltitude: intPtr(150),
+			},
+			statusCode: http.StatusOK,
 		},
 		{
-			name:   "PostEstateIdTree_Error_Length_Out_Off_Range",
-			pathId: "uuid-1",
-			request: args{
-				payload: `{ "x": 10, "y": 10, "height": 40 }`,
-			},
+			name:   "GetEstateId_Error_Not_Found",
+			pathId: "uuid-2",
 			mockFunc: func() {
-				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{
-					Id:       "1",
-					EstateId: "uuid-1",
-					X:        10,
-					Y:        10,
-					Height:   40,
-				}, nil)
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-2").Return(repository.Estate{}, sql.ErrNoRows)
 			},
-			statusCode: http.StatusBadRequest,
+			response:   generated.Estate{},
+			statusCode: http.StatusNotFound,
 		},
 	}
 
@@ -196,55 +310,152 @@ func TestPostEstateIdTree(t *testing.T) {
 			tc.mockFunc()
 
 			e := echo.New()
-
-			path := fmt.Sprintf("/estate/%s/tree", tc.pathId)
-			method := echo.POST
-			req := httptest.NewRequest(method, path, bytes.NewReader([]byte(tc.request.payload)))
-			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/estate/%s", tc.pathId), nil)
 			rr := httptest.NewRecorder()
 			c := e.NewContext(req, rr)
-			_ = server.PostEstateIdTree(c, tc.pathId)
+			handle(c, server.GetEstateId(c, tc.pathId))
 
-			var resp generated.CreateTreeResponse
+			var resp generated.Estate
 			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
 		})
 	}
 }
 
-func TestGetEstateIdStats(t *testing.T) {
+func TestPatchEstateId(t *testing.T) {
+	estate := repository.Estate{
+		Id:        "uuid-1",
+		Width:     10,
+		Length:    5,
+		Latitude:  float64Ptr(-0.5071),
+		Longitude: float64Ptr(101.4478),
+		Bearing:   45,
+		Elevation: 30,
+	}
+
 	testCases := []testCase{
 		{
-			name:   "GetEstateIdStats_Success",
+			name:   "PatchEstateId_Success",
 			pathId: "uuid-1",
+			request: args{
+				payload: `{ "width": 8, "max_altitude": 120 }`,
+			},
 			mockFunc: func() {
-				mockRepo.EXPECT().GetStatsByEstateId(gomock.Any(), "uuid-1").Return(repository.StatsEstate{
-					Count:  10,
-					Min:    10,
-					Max:    25,
-					Median: 20,
-				}, nil)
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().UpdateEstate(gomock.Any(), repository.Estate{
+					Id: "uuid-1", Width: 8, Length: 5, Latitude: float64Ptr(-0.5071), Longitude: float64Ptr(101.4478), Bearing: 45, Elevation: 30, MaxAltitude: intPtr(120),
+				}).DoAndReturn(func(_ context.Context, input repository.Estate) (repository.Estate, error) {
+					return input, nil
+				})
 			},
-			response: generated.GetEstateStatsResponse{
-				Count:  10,
-				Min:    10,
-				Max:    25,
-				Median: 20,
+			response: generated.Estate{
+				Id: "uuid-1", Width: 8, Length: 5, Latitude: float64Ptr(-0.5071), Longitude: float64Ptr(101.4478), Bearing: 45, Elevation: 30, MaxAltitude: intPtr(120),
 			},
 			statusCode: http.StatusOK,
 		},
 		{
-			name:   "GetEstateIdStats_Error",
+			name:   "PatchEstateId_Success_Clear_Location_And_Max_Altitude",
 			pathId: "uuid-1",
+			request: args{
+				payload: `{ "clear_location": true, "clear_max_altitude": true }`,
+			},
 			mockFunc: func() {
-				mockRepo.EXPECT().GetStatsByEstateId(gomock.Any(), "uuid-1").Return(repository.StatsEstate{}, errors.New("error"))
+				withCeiling := estate
+				withCeiling.MaxAltitude = intPtr(120)
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(withCeiling, nil)
+				mockRepo.EXPECT().UpdateEstate(gomock.Any(), repository.Estate{
+					Id: "uuid-1", Width: 10, Length: 5, Bearing: 45, Elevation: 30,
+				}).DoAndReturn(func(_ context.Context, input repository.Estate) (repository.Estate, error) {
+					return input, nil
+				})
 			},
-			response: generated.GetEstateStatsResponse{
-				Count:  0,
-				Min:    0,
-				Max:    0,
-				Median: 0,
+			response: generated.Estate{
+				Id: "uuid-1", Width: 10, Length: 5, Bearing: 45, Elevation: 30,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PatchEstateId_Error_Set_And_Clear_Max_Altitude",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "max_altitude": 120, "clear_max_altitude": true }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.Estate{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PatchEstateId_Error_Set_And_Clear_Location",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "latitude": 1.5, "clear_location": true }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.Estate{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PatchEstateId_Error_Trees_Outside",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "length": 2 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().UpdateEstate(gomock.Any(), gomock.Any()).Return(repository.Estate{}, repository.ErrOutsideBounds)
+			},
+			response:   generated.Estate{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:   "PatchEstateId_Error_Invalid_Width",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "width": 0 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.Estate{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PatchEstateId_Error_Invalid_Max_Altitude",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "max_altitude": 1001 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.Estate{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PatchEstateId_Error_Not_Found",
+			pathId: "uuid-2",
+			request: args{
+				payload: `{ "width": 8 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-2").Return(repository.Estate{}, sql.ErrNoRows)
 			},
+			response:   generated.Estate{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PatchEstateId_Error_Invalid_Body",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "width": "wide" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Estate{},
 			statusCode: http.StatusBadRequest,
 		},
 	}
@@ -256,64 +467,36 @@ func TestGetEstateIdStats(t *testing.T) {
 			tc.mockFunc()
 
 			e := echo.New()
-
-			path := fmt.Sprintf("/estate/%s/stats", tc.pathId)
-			method := echo.GET
-			req := httptest.NewRequest(method, path, nil)
+			req := httptest.NewRequest(echo.PATCH, fmt.Sprintf("/estate/%s", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rr := httptest.NewRecorder()
 			c := e.NewContext(req, rr)
+			handle(c, server.PatchEstateId(c, tc.pathId))
 
-			_ = server.GetEstateIdStats(c, tc.pathId)
-			var resp generated.GetEstateStatsResponse
+			var resp generated.Estate
 			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
-
 			assert.Equal(t, tc.statusCode, rr.Code)
 			assert.Equal(t, tc.response, resp)
 		})
 	}
 }
 
-func TestGetEstateIdDronePlan(t *testing.T) {
+func TestDeleteEstateId(t *testing.T) {
 	testCases := []testCase{
 		{
-			name:   "GetEstateIdDronePlan_Success",
+			name:   "DeleteEstateId_Success",
 			pathId: "uuid-1",
 			mockFunc: func() {
-				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
-					Id:     "uuid-1",
-					Width:  10,
-					Length: 10,
-				}, nil)
-				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
-					{
-						Id:       "uuid-1",
-						EstateId: "uuid-1",
-						X:        10,
-						Y:        10,
-						Height:   10,
-					},
-					{
-						Id:       "uuid-2",
-						EstateId: "uuid-1",
-						X:        5,
-						Y:        6,
-						Height:   15,
-					},
-				}, nil)
-			},
-			response: generated.GetDronePlanResponse{
-				Distance: 205,
+				mockRepo.EXPECT().DeleteEstate(gomock.Any(), "uuid-1").Return(nil)
 			},
-			statusCode: http.StatusOK,
+			statusCode: http.StatusNoContent,
 		},
 		{
-			name:   "GetEstateIdDronePlan_Error_Estate_Not_Found",
+			name:   "DeleteEstateId_Error_Not_Found",
 			pathId: "uuid-1",
 			mockFunc: func() {
-				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+				mockRepo.EXPECT().DeleteEstate(gomock.Any(), "uuid-1").Return(sql.ErrNoRows)
 			},
-			response:   generated.GetDronePlanResponse{},
 			statusCode: http.StatusNotFound,
 		},
 	}
@@ -325,18 +508,3918 @@ func TestGetEstateIdDronePlan(t *testing.T) {
 			tc.mockFunc()
 
 			e := echo.New()
-
-			path := fmt.Sprintf("/estate/%s/drone-plan", tc.pathId)
-			method := echo.GET
-			req := httptest.NewRequest(method, path, nil)
-			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req := httptest.NewRequest(echo.DELETE, fmt.Sprintf("/estate/%s", tc.pathId), nil)
 			rr := httptest.NewRecorder()
 			c := e.NewContext(req, rr)
+			handle(c, server.DeleteEstateId(c, tc.pathId))
 
-			_ = server.GetEstateIdDronePlan(c, tc.pathId)
-			var resp generated.GetDronePlanResponse
-			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
 
+func TestPostEstateIdTree(t *testing.T) {
+	estate := repository.Estate{
+		Id:     "uuid-1",
+		Width:  10,
+		Length: 5,
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PostEstateIdTree_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 10, "y": 5, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{
+					Id:       "1",
+					EstateId: "uuid-1",
+					X:        10,
+					Y:        5,
+					Height:   10,
+				}, nil)
+			},
+			response:   generated.CreateTreeResponse{Id: "1"},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "PostEstateIdTree_Error_X_Out_Off_Range",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": -1, "y": 5, "height": 10 }`,
+			},
+			mockFunc:   func() {},
+			response:   badRequest(generated.OutOfBounds, "Tree is outside the estate").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Y_Out_Off_Range",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 10, "y": -10, "height": 10 }`,
+			},
+			mockFunc:   func() {},
+			response:   badRequest(generated.OutOfBounds, "Tree is outside the estate").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdTree_Error_X_Beyond_Width",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 11, "y": 5, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   badRequest(generated.OutOfBounds, "Tree is outside the estate").withDetail("width", float64(10)).withDetail("length", float64(5)).response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Y_Beyond_Length",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 10, "y": 6, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   badRequest(generated.OutOfBounds, "Tree is outside the estate").withDetail("width", float64(10)).withDetail("length", float64(5)).response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Length_Out_Off_Range",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 10, "y": 5, "height": 40 }`,
+			},
+			mockFunc:   func() {},
+			response:   badRequest(generated.InvalidHeight, "Invalid Height").withField("height").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Estate_Not_Found",
+			pathId: "uuid-2",
+			request: args{
+				payload: `{ "x": 1, "y": 1, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-2").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   notFound(generated.EstateNotFound, "Estate not found").response(),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Plot_Occupied",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 1, "y": 1, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{}, repository.ErrPlotOccupied)
+			},
+			response:   conflict(generated.PlotOccupied, "Plot already holds a tree or an obstacle").response(),
+			statusCode: http.StatusConflict,
+		},
+		{
+			// The estate shrank between reading it and planting the tree.
+			name:   "PostEstateIdTree_Error_Estate_Shrunk",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 1, "y": 1, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{}, repository.ErrOutsideEstate)
+			},
+			response:   badRequest(generated.OutOfBounds, "Tree is outside the estate").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Repository",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 1, "y": 1, "height": 10 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateEstateTree(gomock.Any(), gomock.Any()).Return(repository.EstateTree{}, errors.New("pq: connection refused"))
+			},
+			response:   newError(http.StatusInternalServerError, generated.InternalError, "Internal server error").response(),
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:   "PostEstateIdTree_Error_Invalid_Body",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": "one" }`,
+			},
+			mockFunc:   func() {},
+			response:   invalidBody().response(),
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+
+			path := fmt.Sprintf("/estate/%s/tree", tc.pathId)
+			method := echo.POST
+			req := httptest.NewRequest(method, path, bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostEstateIdTree(c, tc.pathId))
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if rr.Code == http.StatusCreated {
+				var resp generated.CreateTreeResponse
+				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+				assert.Equal(t, tc.response, resp)
+				return
+			}
+			var resp generated.ErrorResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPutTreeIdInspection(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "PutTreeIdInspection_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "needs_inspection": true }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().SetTreeInspection(gomock.Any(), "uuid-1", true).Return(nil)
+			},
+			response:   generated.TreeInspection{NeedsInspection: true},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PutTreeIdInspection_Error_Not_Found",
+			pathId: "uuid-2",
+			request: args{
+				payload: `{ "needs_inspection": false }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().SetTreeInspection(gomock.Any(), "uuid-2", false).Return(sql.ErrNoRows)
+			},
+			response:   generated.TreeInspection{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PutTreeIdInspection_Error_Invalid_Body",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "needs_inspection": "yes" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.TreeInspection{},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.PUT, fmt.Sprintf("/tree/%s/inspection", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PutTreeIdInspection(c, tc.pathId))
+
+			var resp generated.TreeInspection
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPostTreeIdMeasurement(t *testing.T) {
+	measuredAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name:   "PostTreeIdMeasurement_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "height": 12, "measured_at": "2024-05-01T08:00:00Z", "source": "drone" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().RecordTreeMeasurement(gomock.Any(), repository.TreeMeasurement{
+					TreeId:     "uuid-1",
+					Height:     12,
+					MeasuredAt: measuredAt,
+					Source:     repository.MeasurementSourceDrone,
+				}).Return(repository.TreeMeasurement{
+					TreeId:     "uuid-1",
+					Height:     12,
+					MeasuredAt: measuredAt,
+					Source:     repository.MeasurementSourceDrone,
+				}, nil)
+			},
+			response: generated.TreeMeasurement{
+				Height:     12,
+				MeasuredAt: measuredAt,
+				Source:     generated.MeasurementSourceDrone,
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "PostTreeIdMeasurement_Error_Invalid_Height",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "height": 31, "measured_at": "2024-05-01T08:00:00Z", "source": "drone" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.TreeMeasurement{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostTreeIdMeasurement_Error_Missing_Measured_At",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "height": 12, "source": "manual" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.TreeMeasurement{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostTreeIdMeasurement_Error_Measured_In_The_Future",
+			pathId: "uuid-1",
+			request: args{
+				payload: fmt.Sprintf(`{ "height": 12, "measured_at": %q, "source": "manual" }`, time.Now().Add(time.Hour).Format(time.RFC3339)),
+			},
+			mockFunc:   func() {},
+			response:   generated.TreeMeasurement{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostTreeIdMeasurement_Error_Invalid_Source",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "height": 12, "measured_at": "2024-05-01T08:00:00Z", "source": "satellite" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.TreeMeasurement{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostTreeIdMeasurement_Error_Not_Found",
+			pathId: "uuid-2",
+			request: args{
+				payload: `{ "height": 12, "measured_at": "2024-05-01T08:00:00Z", "source": "manual" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().RecordTreeMeasurement(gomock.Any(), gomock.Any()).Return(repository.TreeMeasurement{}, sql.ErrNoRows)
+			},
+			response:   generated.TreeMeasurement{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, fmt.Sprintf("/tree/%s/measurement", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostTreeIdMeasurement(c, tc.pathId))
+
+			var resp generated.TreeMeasurement
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetTreeIdMeasurement(t *testing.T) {
+	planted := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// A year of 365.25 days after planting.
+	measured := time.Date(2024, 12, 31, 6, 0, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name:   "GetTreeIdMeasurement_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreeById(gomock.Any(), "uuid-1").Return(repository.EstateTree{
+					Id:       "uuid-1",
+					EstateId: "uuid-1",
+					X:        1,
+					Y:        1,
+					Height:   12,
+				}, nil)
+				mockRepo.EXPECT().GetTreeMeasurements(gomock.Any(), "uuid-1").Return([]repository.TreeMeasurement{
+					{TreeId: "uuid-1", Height: 10, MeasuredAt: planted, Source: repository.MeasurementSourceManual},
+					{TreeId: "uuid-1", Height: 12, MeasuredAt: measured, Source: repository.MeasurementSourceDrone},
+				}, nil)
+			},
+			response: generated.TreeGrowthResponse{
+				TreeId:     "uuid-1",
+				Height:     12,
+				GrowthRate: float64Ptr(2),
+				Measurements: []generated.TreeMeasurement{
+					{Height: 10, MeasuredAt: planted, Source: generated.MeasurementSourceManual},
+					{Height: 12, MeasuredAt: measured, Source: generated.MeasurementSourceDrone},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetTreeIdMeasurement_Success_Measured_Once",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreeById(gomock.Any(), "uuid-1").Return(repository.EstateTree{
+					Id:     "uuid-1",
+					Height: 10,
+				}, nil)
+				mockRepo.EXPECT().GetTreeMeasurements(gomock.Any(), "uuid-1").Return([]repository.TreeMeasurement{
+					{TreeId: "uuid-1", Height: 10, MeasuredAt: planted, Source: repository.MeasurementSourceManual},
+				}, nil)
+			},
+			response: generated.TreeGrowthResponse{
+				TreeId: "uuid-1",
+				Height: 10,
+				Measurements: []generated.TreeMeasurement{
+					{Height: 10, MeasuredAt: planted, Source: generated.MeasurementSourceManual},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetTreeIdMeasurement_Error_Not_Found",
+			pathId: "uuid-2",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreeById(gomock.Any(), "uuid-2").Return(repository.EstateTree{}, sql.ErrNoRows)
+			},
+			response:   generated.TreeGrowthResponse{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "GetTreeIdMeasurement_Error_Repository",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreeById(gomock.Any(), "uuid-1").Return(repository.EstateTree{Id: "uuid-1"}, nil)
+				mockRepo.EXPECT().GetTreeMeasurements(gomock.Any(), "uuid-1").Return(nil, errors.New("error"))
+			},
+			response:   generated.TreeGrowthResponse{},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/tree/%s/measurement", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetTreeIdMeasurement(c, tc.pathId))
+
+			var resp generated.TreeGrowthResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdGrowth(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdGrowth_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1"}, nil)
+				mockRepo.EXPECT().GetEstateGrowth(gomock.Any(), "uuid-1").Return(repository.EstateGrowth{
+					TreeCount:   3,
+					AverageRate: 1.5,
+				}, nil)
+			},
+			response: generated.EstateGrowthResponse{
+				TreeCount:         3,
+				AverageGrowthRate: 1.5,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdGrowth_Error_Estate_Not_Found",
+			pathId: "uuid-2",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-2").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.EstateGrowthResponse{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "GetEstateIdGrowth_Error_Repository",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1"}, nil)
+				mockRepo.EXPECT().GetEstateGrowth(gomock.Any(), "uuid-1").Return(repository.EstateGrowth{}, errors.New("error"))
+			},
+			response:   generated.EstateGrowthResponse{},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/estate/%s/growth", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetEstateIdGrowth(c, tc.pathId))
+
+			var resp generated.EstateGrowthResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdStats(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdStats_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetStatsByEstateId(gomock.Any(), "uuid-1").Return(repository.StatsEstate{
+					Count:  10,
+					Min:    10,
+					Max:    25,
+					Median: 20,
+				}, nil)
+			},
+			response: generated.GetEstateStatsResponse{
+				Count:  10,
+				Min:    10,
+				Max:    25,
+				Median: 20,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdStats_Error",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetStatsByEstateId(gomock.Any(), "uuid-1").Return(repository.StatsEstate{}, errors.New("error"))
+			},
+			response: generated.GetEstateStatsResponse{
+				Count:  0,
+				Min:    0,
+				Max:    0,
+				Median: 0,
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+
+			path := fmt.Sprintf("/estate/%s/stats", tc.pathId)
+			method := echo.GET
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+
+			handle(c, server.GetEstateIdStats(c, tc.pathId))
+			var resp generated.GetEstateStatsResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdDronePlan(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdDronePlan_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  10,
+					Length: 10,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{
+						Id:       "uuid-1",
+						EstateId: "uuid-1",
+						X:        10,
+						Y:        10,
+						Height:   10,
+					},
+					{
+						Id:       "uuid-2",
+						EstateId: "uuid-1",
+						X:        5,
+						Y:        6,
+						Height:   15,
+					},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 1042,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Without_Trees",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 52,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_With_Max_Distance",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				MaxDistance: intPtr(40),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+					{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 32,
+				Rest: &generated.DronePlanRest{
+					X: 3,
+					Y: 1,
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_With_Drones",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Drones: intPtr(2),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+					{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 52,
+				Drones: &[]generated.DronePlanSection{
+					{
+						Drone:    1,
+						Start:    generated.EstatePlot{X: 1, Y: 1},
+						End:      generated.EstatePlot{X: 3, Y: 1},
+						Distance: 32,
+					},
+					{
+						Drone:    2,
+						Start:    generated.EstatePlot{X: 4, Y: 1},
+						End:      generated.EstatePlot{X: 5, Y: 1},
+						Distance: 20,
+					},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_With_Drone",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				DroneId: stringPtr("123e4567-e89b-12d3-a456-426614174000"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(repository.Drone{
+					Id:          "123e4567-e89b-12d3-a456-426614174000",
+					Model:       "Survey X4",
+					CruiseSpeed: 10,
+					ClimbRate:   1,
+					DescentRate: 2,
+					Endurance:   40,
+					Clearance:   1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+					{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance:     62,
+				Clearance:    intPtr(1),
+				FlightTime:   float64Ptr(20.5),
+				BatterySwaps: intPtr(1),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_With_Drone_Clearance",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				DroneId: stringPtr("123e4567-e89b-12d3-a456-426614174000"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(repository.Drone{
+					Id:          "123e4567-e89b-12d3-a456-426614174000",
+					Model:       "Survey X4",
+					CruiseSpeed: 10,
+					ClimbRate:   2,
+					DescentRate: 2,
+					Endurance:   1000,
+					Clearance:   5,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance:     60,
+				Clearance:    intPtr(5),
+				FlightTime:   float64Ptr(10),
+				BatterySwaps: intPtr(0),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Drone_Endurance_Too_Short",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				DroneId: stringPtr("123e4567-e89b-12d3-a456-426614174000"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(repository.Drone{
+					Id:          "123e4567-e89b-12d3-a456-426614174000",
+					Model:       "Survey X4",
+					CruiseSpeed: 10,
+					ClimbRate:   1,
+					DescentRate: 1,
+					Endurance:   15,
+					Clearance:   1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Drone_Not_Found",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				DroneId: stringPtr("123e4567-e89b-12d3-a456-426614174000"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(repository.Drone{}, sql.ErrNoRows)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Malformed_Drone_Id",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				DroneId: stringPtr("drone-1"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Drone_With_Max_Distance",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				DroneId:     stringPtr("123e4567-e89b-12d3-a456-426614174000"),
+				MaxDistance: intPtr(40),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_More_Drones_Than_Plots",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Drones: intPtr(3),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  2,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Drones",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Drones: intPtr(0),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Max_Distance",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				MaxDistance: intPtr(0),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Format",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Format: formatPtr("csv"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Max_Distance_With_QGC_Format",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				MaxDistance: intPtr(40),
+				Format:      formatPtr("qgc"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_KML_Format_Without_Location",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Format: formatPtr("kml"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  10,
+					Length: 10,
+				}, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Climb_Over_Obstacles",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 3, Y: 1, Height: 5},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return([]repository.Obstacle{
+					{Id: "obstacle-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 40, Kind: "water tower"},
+				}, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 102,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Over_Hilly_Ground",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:        "uuid-1",
+					Width:     3,
+					Length:    1,
+					Elevation: 100,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5, Elevation: 104},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return([]repository.PlotElevation{
+					{EstateId: "uuid-1", X: 2, Y: 1, Elevation: 104},
+				}, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 40,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Region",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				FromX: intPtr(3),
+				FromY: intPtr(1),
+				ToX:   intPtr(3),
+				ToY:   intPtr(1),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesInArea(gomock.Any(), "uuid-1", repository.Area{FromX: 1, FromY: 1, ToX: 3, ToY: 1}).Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+				}, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 62,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Inspection",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				InspectionDescent: intPtr(8),
+				InspectionHover:   float64Ptr(15),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5, NeedsInspection: true},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 10},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			// The drone never descends below its clearance above the
+			// treetop, which it already cruises at.
+			response: generated.GetDronePlanResponse{
+				Distance: 42,
+				Inspection: &generated.DronePlanInspection{
+					Stops: 1,
+					Time:  15,
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Inspection_Descent",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				InspectionDescent: intPtr(0),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Inspection_Hover",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				InspectionHover: float64Ptr(-1),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Ceiling_Flag",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:          "uuid-1",
+					Width:       3,
+					Length:      3,
+					MaxAltitude: intPtr(10),
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "tree-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 10},
+					{Id: "tree-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 5},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return([]repository.Obstacle{
+					{Id: "obstacle-1", EstateId: "uuid-1", X: 3, Y: 3, Height: 40, Kind: "tower"},
+				}, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 182,
+				Ceiling: &generated.DronePlanCeiling{
+					MaxAltitude: 10,
+					TreeIds:     []string{"tree-1"},
+					ObstacleIds: []string{"obstacle-1"},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Ceiling_Skip",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Ceiling: ceilingPtr("skip"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:          "uuid-1",
+					Width:       3,
+					Length:      3,
+					MaxAltitude: intPtr(10),
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "tree-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 10},
+					{Id: "tree-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 5},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return([]repository.Obstacle{
+					{Id: "obstacle-1", EstateId: "uuid-1", X: 3, Y: 3, Height: 40, Kind: "tower"},
+				}, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance:         72,
+				SkippedPlotCount: 2,
+				Skipped: &[]generated.DronePlanSkippedArea{
+					{From: generated.EstatePlot{X: 2, Y: 1}, To: generated.EstatePlot{X: 2, Y: 1}, Reason: generated.AboveCeiling},
+					{From: generated.EstatePlot{X: 3, Y: 3}, To: generated.EstatePlot{X: 3, Y: 3}, Reason: generated.AboveCeiling},
+				},
+				Ceiling: &generated.DronePlanCeiling{
+					MaxAltitude: 10,
+					Skipped:     true,
+					TreeIds:     []string{"tree-1"},
+					ObstacleIds: []string{"obstacle-1"},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Ceiling_Skip_Limit_Exceeded",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Ceiling: ceilingPtr("skip"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:          "uuid-1",
+					Width:       200,
+					Length:      1,
+					MaxAltitude: intPtr(10),
+				}, nil)
+				trees := make([]repository.EstateTree, maxSkippedAboveCeiling+1)
+				for i := range trees {
+					trees[i] = repository.EstateTree{Id: fmt.Sprintf("tree-%d", i), EstateId: "uuid-1", X: i + 1, Y: 1, Height: 10}
+				}
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Ceiling",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Ceiling: ceilingPtr("lower"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Pattern",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Pattern: patternPtr("columns"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 22,
+				Pattern:  stringPtr("columns"),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Pattern_Auto",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Pattern: patternPtr("auto"),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 5,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 3, Y: 1, Height: 30},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 2, Height: 30},
+					{Id: "uuid-3", EstateId: "uuid-1", X: 3, Y: 3, Height: 30},
+					{Id: "uuid-4", EstateId: "uuid-1", X: 3, Y: 4, Height: 30},
+					{Id: "uuid-5", EstateId: "uuid-1", X: 3, Y: 5, Height: 30},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance: 302,
+				Pattern:  stringPtr("columns"),
+				Candidates: &[]generated.DronePlanCandidate{
+					{Pattern: "rows", Distance: 542},
+					{Pattern: "rows-se", Distance: 542},
+					{Pattern: "rows-nw", Distance: 542},
+					{Pattern: "rows-ne", Distance: 542},
+					{Pattern: "columns", Distance: 302},
+					{Pattern: "spiral", Distance: 542},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Invalid_Pattern",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				Pattern: patternPtr("diagonal"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Region_Partial",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				FromX: intPtr(3),
+				FromY: intPtr(1),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Region_Outside_The_Estate",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				FromX: intPtr(1),
+				FromY: intPtr(1),
+				ToX:   intPtr(4),
+				ToY:   intPtr(2),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Region_Unreachable",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanParams{
+				FromX: intPtr(3),
+				FromY: intPtr(2),
+				ToX:   intPtr(3),
+				ToY:   intPtr(2),
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return([]repository.NoFlyZone{
+					{Id: "zone-1", EstateId: "uuid-1", FromX: 2, FromY: 1, ToX: 2, ToY: 2},
+				}, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Success_Around_No_Fly_Zones",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 3,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return([]repository.NoFlyZone{
+					{Id: "zone-1", EstateId: "uuid-1", FromX: 3, FromY: 1, ToX: 3, ToY: 3},
+				}, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetDronePlanResponse{
+				Distance:         52,
+				SkippedPlotCount: 9,
+				Skipped: &[]generated.DronePlanSkippedArea{
+					{From: generated.EstatePlot{X: 3, Y: 1}, To: generated.EstatePlot{X: 3, Y: 3}, Reason: generated.DronePlanSkippedAreaReason("no-fly")},
+					{From: generated.EstatePlot{X: 4, Y: 1}, To: generated.EstatePlot{X: 5, Y: 3}, Reason: generated.DronePlanSkippedAreaReason("unreachable")},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_No_Fly_Zones_Cover_The_Estate",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  3,
+					Length: 2,
+				}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return([]repository.NoFlyZone{
+					{Id: "zone-1", EstateId: "uuid-1", FromX: 1, FromY: 1, ToX: 3, ToY: 2},
+				}, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlan_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetDronePlanResponse{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+
+			path := fmt.Sprintf("/estate/%s/drone-plan", tc.pathId)
+			method := echo.GET
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+
+			params, _ := tc.params.(generated.GetEstateIdDronePlanParams)
+			handle(c, server.GetEstateIdDronePlan(c, tc.pathId, params))
+			var resp generated.GetDronePlanResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdDronePlanQGC(t *testing.T) {
+	initialize(t)
+
+	mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+		Id:     "uuid-1",
+		Width:  5,
+		Length: 1,
+	}, nil)
+	mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+		{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+		{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+		{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+	}, nil)
+	mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/estate/uuid-1/drone-plan?format=qgc", nil)
+	rr := httptest.NewRecorder()
+	c := e.NewContext(req, rr)
+
+	_ = server.GetEstateIdDronePlan(c, "uuid-1", generated.GetEstateIdDronePlanParams{
+		Format: formatPtr("qgc"),
+	})
+	var resp droneplan.QGCPlan
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/vnd.qgroundcontrol.plan+json", rr.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="uuid-1.plan"`, rr.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "Plan", resp.FileType)
+	assert.Len(t, resp.Mission.Items, 6)
+}
+
+func TestGetEstateIdDronePlanGeoJSON(t *testing.T) {
+	initialize(t)
+
+	mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+		Id:        "uuid-1",
+		Width:     3,
+		Length:    2,
+		Latitude:  float64Ptr(-0.5071),
+		Longitude: float64Ptr(101.4478),
+	}, nil)
+	mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/estate/uuid-1/drone-plan?format=geojson", nil)
+	rr := httptest.NewRecorder()
+	c := e.NewContext(req, rr)
+
+	_ = server.GetEstateIdDronePlan(c, "uuid-1", generated.GetEstateIdDronePlanParams{
+		Format: formatPtr("geojson"),
+	})
+	var resp struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/geo+json", rr.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="uuid-1.geojson"`, rr.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "FeatureCollection", resp.Type)
+	assert.Len(t, resp.Features, 3)
+	assert.Equal(t, 52.0, resp.Features[0].Properties["distance"])
+}
+
+func TestGetEstateIdDronePlanSvg(t *testing.T) {
+	initialize(t)
+
+	mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+		Id:     "uuid-1",
+		Width:  5,
+		Length: 1,
+	}, nil)
+	mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+		{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+	}, nil)
+	mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/estate/uuid-1/drone-plan.svg", nil)
+	rr := httptest.NewRecorder()
+	c := e.NewContext(req, rr)
+
+	handle(c, server.GetEstateIdDronePlanSvg(c, "uuid-1"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, droneplan.MIMESVG, rr.Header().Get(echo.HeaderContentType))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "<svg "))
+	assert.Contains(t, rr.Body.String(), `<title>Tree (2, 1), 5 m</title>`)
+}
+
+func TestGetEstateIdDronePlanSvgTooLarge(t *testing.T) {
+	initialize(t)
+
+	mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+		Id:     "uuid-1",
+		Width:  50000,
+		Length: 50000,
+	}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/estate/uuid-1/drone-plan.svg", nil)
+	rr := httptest.NewRecorder()
+	c := e.NewContext(req, rr)
+
+	handle(c, server.GetEstateIdDronePlanSvg(c, "uuid-1"))
+	var resp generated.ErrorResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Estate is too large to draw", resp.Message)
+}
+
+func TestGetEstateIdTreesExport(t *testing.T) {
+	located := repository.Estate{
+		Id:        "uuid-1",
+		Width:     10,
+		Length:    10,
+		Latitude:  float64Ptr(-0.5071),
+		Longitude: float64Ptr(101.4478),
+	}
+
+	testCases := []struct {
+		name        string
+		format      string
+		mockFunc    func()
+		statusCode  int
+		contentType string
+	}{
+		{
+			name:   "GetEstateIdTreesExport_Success_KML",
+			format: "kml",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(located, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "tree-1", EstateId: "uuid-1", X: 2, Y: 3, Height: 10},
+				}, nil)
+			},
+			statusCode:  http.StatusOK,
+			contentType: "application/vnd.google-earth.kml+xml",
+		},
+		{
+			name:   "GetEstateIdTreesExport_Success_GeoJSON",
+			format: "geojson",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(located, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "tree-1", EstateId: "uuid-1", X: 2, Y: 3, Height: 10},
+				}, nil)
+			},
+			statusCode:  http.StatusOK,
+			contentType: "application/geo+json",
+		},
+		{
+			name:   "GetEstateIdTreesExport_Error_Estate_Without_Location",
+			format: "kml",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  10,
+					Length: 10,
+				}, nil)
+			},
+			statusCode:  http.StatusBadRequest,
+			contentType: echo.MIMEApplicationJSON,
+		},
+		{
+			name:   "GetEstateIdTreesExport_Error_Estate_Not_Found",
+			format: "kml",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			statusCode:  http.StatusNotFound,
+			contentType: echo.MIMEApplicationJSON,
+		},
+		{
+			name:        "GetEstateIdTreesExport_Error_Invalid_Format",
+			format:      "csv",
+			mockFunc:    func() {},
+			statusCode:  http.StatusBadRequest,
+			contentType: echo.MIMEApplicationJSON,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+
+			path := fmt.Sprintf("/estate/uuid-1/trees/export?format=%s", tc.format)
+			req := httptest.NewRequest(echo.GET, path, nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+
+			handle(c, server.GetEstateIdTreesExport(c, "uuid-1", generated.GetEstateIdTreesExportParams{
+				Format: generated.GetEstateIdTreesExportParamsFormat(tc.format),
+			}))
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Contains(t, rr.Header().Get(echo.HeaderContentType), tc.contentType)
+		})
+	}
+}
+
+func TestGetEstateIdTrees(t *testing.T) {
+	mockEstate := func() {
+		mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+			Id:     "uuid-1",
+			Width:  10,
+			Length: 10,
+		}, nil)
+	}
+
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdTrees_Success_First_Page",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				Limit: intPtr(2),
+			},
+			mockFunc: func() {
+				mockEstate()
+				mockRepo.EXPECT().ListTrees(gomock.Any(), repository.TreeFilter{
+					EstateId: "uuid-1",
+					Sort:     repository.TreeSortPosition,
+					Limit:    3,
+				}).Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 1, Y: 1, Height: 5, Elevation: 100},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 1, Y: 2, Height: 7, Elevation: 100, NeedsInspection: true},
+					{Id: "uuid-3", EstateId: "uuid-1", X: 2, Y: 1, Height: 3, Elevation: 100},
+				}, nil)
+			},
+			response: generated.GetTreesResponse{
+				Trees: []generated.Tree{
+					{Id: "uuid-1", X: 1, Y: 1, Height: 5, Elevation: 100},
+					{Id: "uuid-2", X: 1, Y: 2, Height: 7, Elevation: 100, NeedsInspection: true},
+				},
+				NextCursor: stringPtr("cG9zaXRpb246ZmFsc2U6MDoxOjI"),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdTrees_Success_Filtered_Last_Page",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				MinHeight: intPtr(10),
+				MaxHeight: intPtr(20),
+				MinX:      intPtr(1),
+				MinY:      intPtr(1),
+				MaxX:      intPtr(5),
+				MaxY:      intPtr(5),
+				Sort:      sortPtr("height"),
+				Order:     orderPtr("desc"),
+				Cursor:    stringPtr("aGVpZ2h0OnRydWU6MTU6Mzo0"),
+			},
+			mockFunc: func() {
+				mockEstate()
+				mockRepo.EXPECT().ListTrees(gomock.Any(), repository.TreeFilter{
+					EstateId:   "uuid-1",
+					MinHeight:  intPtr(10),
+					MaxHeight:  intPtr(20),
+					Area:       &repository.Area{FromX: 1, FromY: 1, ToX: 5, ToY: 5},
+					Sort:       repository.TreeSortHeight,
+					Descending: true,
+					After:      &repository.TreeKey{Key: 15, X: 3, Y: 4},
+					Limit:      101,
+				}).Return([]repository.EstateTree{
+					{Id: "uuid-4", EstateId: "uuid-1", X: 2, Y: 2, Height: 12},
+				}, nil)
+			},
+			response: generated.GetTreesResponse{
+				Trees: []generated.Tree{
+					{Id: "uuid-4", X: 2, Y: 2, Height: 12},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdTrees_Success_Radius_By_Distance",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				NearX:  intPtr(3),
+				NearY:  intPtr(3),
+				Radius: intPtr(2),
+				Sort:   sortPtr("distance"),
+				Limit:  intPtr(1),
+			},
+			mockFunc: func() {
+				mockEstate()
+				mockRepo.EXPECT().ListTrees(gomock.Any(), repository.TreeFilter{
+					EstateId: "uuid-1",
+					Near:     &repository.Circle{X: 3, Y: 3, Radius: 2},
+					Sort:     repository.TreeSortDistance,
+					Limit:    2,
+				}).Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 4, Y: 3, Height: 5},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 1, Y: 3, Height: 5},
+				}, nil)
+			},
+			response: generated.GetTreesResponse{
+				Trees: []generated.Tree{
+					{Id: "uuid-1", X: 4, Y: 3, Height: 5},
+				},
+				NextCursor: stringPtr("ZGlzdGFuY2U6ZmFsc2U6MTo0OjM"),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Invalid_Height_Range",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				MinHeight: intPtr(20),
+				MaxHeight: intPtr(10),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Partial_Box",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				MinX: intPtr(1),
+				MaxX: intPtr(5),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Distance_Without_Radius",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				Sort: sortPtr("distance"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Cursor_Of_Another_Order",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				Sort:   sortPtr("height"),
+				Cursor: stringPtr("aGVpZ2h0OnRydWU6MTU6Mzo0"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Limit_Exceeded",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdTreesParams{
+				Limit: intPtr(1001),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "GetEstateIdTrees_Error_Repository",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockEstate()
+				mockRepo.EXPECT().ListTrees(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			response:   generated.GetTreesResponse{},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+
+			path := fmt.Sprintf("/estate/%s/trees", tc.pathId)
+			req := httptest.NewRequest(echo.GET, path, nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+
+			params, _ := tc.params.(generated.GetEstateIdTreesParams)
+			handle(c, server.GetEstateIdTrees(c, tc.pathId, params))
+			var resp generated.GetTreesResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdDronePlanWaypoints(t *testing.T) {
+	mockEstate := func() {
+		mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+			Id:     "uuid-1",
+			Width:  5,
+			Length: 1,
+		}, nil)
+		mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+			{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+			{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+			{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+		}, nil)
+		mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+		mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+		mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	}
+
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdDronePlanWaypoints_Success_First_Page",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanWaypointsParams{
+				Limit: intPtr(3),
+			},
+			mockFunc: mockEstate,
+			response: generated.GetDronePlanWaypointsResponse{
+				Waypoints: []generated.DronePlanWaypoint{
+					{X: 1, Y: 1, Altitude: 0, Distance: 0},
+					{X: 1, Y: 1, Altitude: 1, Distance: 1},
+					{X: 2, Y: 1, Altitude: 6, Distance: 16},
+				},
+				NextCursor: stringPtr("3"),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlanWaypoints_Success_Last_Page",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanWaypointsParams{
+				Cursor: stringPtr("3"),
+				Limit:  intPtr(10),
+			},
+			mockFunc: mockEstate,
+			response: generated.GetDronePlanWaypointsResponse{
+				Waypoints: []generated.DronePlanWaypoint{
+					{X: 3, Y: 1, Altitude: 4, Distance: 28},
+					{X: 4, Y: 1, Altitude: 5, Distance: 39},
+					{X: 5, Y: 1, Altitude: 1, Distance: 53},
+					{X: 5, Y: 1, Altitude: 0, Distance: 54},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdDronePlanWaypoints_Error_Invalid_Cursor",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdDronePlanWaypointsParams{
+				Cursor: stringPtr("abc"),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetDronePlanWaypointsResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdDronePlanWaypoints_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetDronePlanWaypointsResponse{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+
+			path := fmt.Sprintf("/estate/%s/drone-plan/waypoints", tc.pathId)
+			method := echo.GET
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+
+			params, _ := tc.params.(generated.GetEstateIdDronePlanWaypointsParams)
+			handle(c, server.GetEstateIdDronePlanWaypoints(c, tc.pathId, params))
+			var resp generated.GetDronePlanWaypointsResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPostEstateIdDronePlanSimulate(t *testing.T) {
+	estate := repository.Estate{
+		Id:     "uuid-1",
+		Width:  3,
+		Length: 1,
+	}
+	// The drone climbs 5 metres over the tree and back: 22 + 10.
+	trees := []repository.EstateTree{
+		{Id: "tree-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PostEstateIdDronePlanSimulate_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "remove": ["tree-1"], "add": [{ "x": 3, "y": 1, "height": 10 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response: generated.SimulateDronePlanResponse{
+				OldDistance: 32,
+				NewDistance: 42,
+				Difference:  10,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Success_Update",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "update": [{ "id": "tree-1", "height": 1 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response: generated.SimulateDronePlanResponse{
+				OldDistance: 32,
+				NewDistance: 24,
+				Difference:  -8,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Success_Replant_Removed_Plot",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "remove": ["tree-1"], "add": [{ "x": 2, "y": 1, "height": 5 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response: generated.SimulateDronePlanResponse{
+				OldDistance: 32,
+				NewDistance: 32,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Unknown_Tree",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "remove": ["tree-2"] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Removed_And_Updated",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "remove": ["tree-1"], "update": [{ "id": "tree-1", "height": 3 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Plot_Occupied",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "add": [{ "x": 3, "y": 1, "height": 5 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return([]repository.Obstacle{
+					{Id: "obstacle-1", EstateId: "uuid-1", X: 3, Y: 1, Height: 20, Kind: "tower"},
+				}, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Outside_The_Estate",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "add": [{ "x": 4, "y": 1, "height": 5 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Invalid_Height",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "update": [{ "id": "tree-1", "height": 31 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(trees, nil)
+			},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Estate_Not_Found",
+			pathId: "uuid-2",
+			request: args{
+				payload: `{}`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-2").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PostEstateIdDronePlanSimulate_Error_Invalid_Body",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "add": 1 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.SimulateDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, fmt.Sprintf("/estate/%s/drone-plan/simulate", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostEstateIdDronePlanSimulate(c, tc.pathId))
+
+			var resp generated.SimulateDronePlanResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPostDronePlansBatch(t *testing.T) {
+	estates := map[string]repository.Estate{
+		"123e4567-e89b-12d3-a456-426614174001": {Id: "123e4567-e89b-12d3-a456-426614174001", Width: 3, Length: 1},
+		"123e4567-e89b-12d3-a456-426614174002": {Id: "123e4567-e89b-12d3-a456-426614174002", Width: 3, Length: 1},
+	}
+	// The drone climbs 5 metres over the tree of the first estate and back:
+	// 22 + 10, and flies straight over the second: 22.
+	trees := []repository.EstateTree{
+		{Id: "tree-1", EstateId: "123e4567-e89b-12d3-a456-426614174001", X: 2, Y: 1, Height: 5},
+	}
+
+	tooMany := make([]string, maxBatchEstates+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf(`"uuid-%d"`, i)
+	}
+
+	testCases := []testCase{
+		{
+			name: "PostDronePlansBatch_Success",
+			request: args{
+				payload: `{ "estate_ids": ["123e4567-e89b-12d3-a456-426614174001", "123e4567-e89b-12d3-a456-426614174002", "123e4567-e89b-12d3-a456-426614174003"] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreesByEstateIds(gomock.Any(), []string{"123e4567-e89b-12d3-a456-426614174001", "123e4567-e89b-12d3-a456-426614174002", "123e4567-e89b-12d3-a456-426614174003"}).Return(trees, nil)
+				for _, id := range []string{"123e4567-e89b-12d3-a456-426614174001", "123e4567-e89b-12d3-a456-426614174002"} {
+					mockRepo.EXPECT().GetEstateById(gomock.Any(), id).Return(estates[id], nil)
+					mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), id).Return(nil, nil)
+					mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), id).Return(nil, nil)
+					mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), id).Return(nil, nil)
+				}
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174003").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response: generated.BatchDronePlanResponse{
+				Plans: []generated.BatchDronePlan{
+					{EstateId: "123e4567-e89b-12d3-a456-426614174001", Distance: intPtr(32), SkippedPlotCount: intPtr(0)},
+					{EstateId: "123e4567-e89b-12d3-a456-426614174002", Distance: intPtr(22), SkippedPlotCount: intPtr(0)},
+					{EstateId: "123e4567-e89b-12d3-a456-426614174003", Error: stringPtr("Estate not found")},
+				},
+				Totals: generated.BatchDronePlanTotals{
+					Estates:  3,
+					Planned:  2,
+					Failed:   1,
+					Distance: 54,
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "PostDronePlansBatch_Success_Unreachable",
+			request: args{
+				payload: `{ "estate_ids": ["123e4567-e89b-12d3-a456-426614174001"] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreesByEstateIds(gomock.Any(), []string{"123e4567-e89b-12d3-a456-426614174001"}).Return(trees, nil)
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174001").Return(estates["123e4567-e89b-12d3-a456-426614174001"], nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "123e4567-e89b-12d3-a456-426614174001").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "123e4567-e89b-12d3-a456-426614174001").Return([]repository.NoFlyZone{
+					{Id: "zone-1", EstateId: "123e4567-e89b-12d3-a456-426614174001", FromX: 1, FromY: 1, ToX: 3, ToY: 1},
+				}, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "123e4567-e89b-12d3-a456-426614174001").Return(nil, nil)
+			},
+			response: generated.BatchDronePlanResponse{
+				Plans: []generated.BatchDronePlan{
+					{EstateId: "123e4567-e89b-12d3-a456-426614174001", Error: stringPtr("No-fly zones make the estate unreachable")},
+				},
+				Totals: generated.BatchDronePlanTotals{
+					Estates: 1,
+					Failed:  1,
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "PostDronePlansBatch_Error_Repository",
+			request: args{
+				payload: `{ "estate_ids": ["123e4567-e89b-12d3-a456-426614174001"] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetTreesByEstateIds(gomock.Any(), []string{"123e4567-e89b-12d3-a456-426614174001"}).Return(nil, errors.New("error"))
+			},
+			response:   generated.BatchDronePlanResponse{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "PostDronePlansBatch_Error_No_Estates",
+			request: args{
+				payload: `{ "estate_ids": [] }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.BatchDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDronePlansBatch_Error_Too_Many_Estates",
+			request: args{
+				payload: `{ "estate_ids": [` + strings.Join(tooMany, ",") + `] }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.BatchDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDronePlansBatch_Error_Malformed_Estate_Id",
+			request: args{
+				payload: `{ "estate_ids": ["123e4567-e89b-12d3-a456-426614174001", "uuid-2"] }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.BatchDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDronePlansBatch_Error_Duplicate_Estate",
+			request: args{
+				payload: `{ "estate_ids": ["123e4567-e89b-12d3-a456-426614174001", "123e4567-e89b-12d3-a456-426614174001"] }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.BatchDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDronePlansBatch_Error_Invalid_Body",
+			request: args{
+				payload: `{ "estate_ids": 1 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.BatchDronePlanResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, "/drone-plans/batch", bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostDronePlansBatch(c))
+
+			var resp generated.BatchDronePlanResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPostEstateIdObstacle(t *testing.T) {
+	estate := repository.Estate{
+		Id:     "uuid-1",
+		Width:  10,
+		Length: 5,
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PostEstateIdObstacle_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 2, "y": 3, "height": 40, "kind": "water tower" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateObstacle(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input repository.Obstacle) (repository.Obstacle, error) {
+					input.Id = "obstacle-1"
+					return input, nil
+				})
+			},
+			response: generated.Obstacle{
+				Id:       "obstacle-1",
+				EstateId: "uuid-1",
+				X:        2,
+				Y:        3,
+				Height:   40,
+				Kind:     "water tower",
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "PostEstateIdObstacle_Error_Invalid_Height",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 2, "y": 3, "height": 301, "kind": "water tower" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Obstacle{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdObstacle_Error_Missing_Kind",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 2, "y": 3, "height": 40, "kind": " " }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Obstacle{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdObstacle_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 2, "y": 3, "height": 40, "kind": "water tower" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.Obstacle{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PostEstateIdObstacle_Error_Outside_The_Estate",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 11, "y": 3, "height": 40, "kind": "water tower" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.Obstacle{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdObstacle_Error_Plot_Occupied",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 2, "y": 3, "height": 40, "kind": "water tower" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateObstacle(gomock.Any(), gomock.Any()).Return(repository.Obstacle{}, repository.ErrPlotOccupied)
+			},
+			response:   generated.Obstacle{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:   "PostEstateIdObstacle_Error_Estate_Shrunk",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "x": 2, "y": 3, "height": 40, "kind": "water tower" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().CreateObstacle(gomock.Any(), gomock.Any()).Return(repository.Obstacle{}, repository.ErrOutsideEstate)
+			},
+			response:   generated.Obstacle{},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, fmt.Sprintf("/estate/%s/obstacle", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostEstateIdObstacle(c, tc.pathId))
+
+			var resp generated.Obstacle
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdObstacle(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdObstacle_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 10, Length: 5}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return([]repository.Obstacle{
+					{Id: "obstacle-1", EstateId: "uuid-1", X: 2, Y: 3, Height: 40, Kind: "water tower"},
+				}, nil)
+			},
+			response: generated.GetObstaclesResponse{
+				Obstacles: []generated.Obstacle{
+					{
+						Id:       "obstacle-1",
+						EstateId: "uuid-1",
+						X:        2,
+						Y:        3,
+						Height:   40,
+						Kind:     "water tower",
+					},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdObstacle_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetObstaclesResponse{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/estate/%s/obstacle", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetEstateIdObstacle(c, tc.pathId))
+
+			var resp generated.GetObstaclesResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestDeleteObstacleId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "DeleteObstacleId_Success",
+			pathId: "obstacle-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteObstacle(gomock.Any(), "obstacle-1").Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:   "DeleteObstacleId_Error_Not_Found",
+			pathId: "obstacle-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteObstacle(gomock.Any(), "obstacle-1").Return(sql.ErrNoRows)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.DELETE, fmt.Sprintf("/obstacle/%s", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.DeleteObstacleId(c, tc.pathId))
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
+
+func TestPostEstateIdNoFlyZone(t *testing.T) {
+	estate := repository.Estate{
+		Id:     "uuid-1",
+		Width:  10,
+		Length: 5,
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PostEstateIdNoFlyZone_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "from": { "x": 4, "y": 5 }, "to": { "x": 2, "y": 3 } }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().CreateNoFlyZone(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input repository.NoFlyZone) (repository.NoFlyZone, error) {
+					input.Id = "zone-1"
+					return input, nil
+				})
+			},
+			response: generated.NoFlyZone{
+				Id:       "zone-1",
+				EstateId: "uuid-1",
+				From:     generated.EstatePlot{X: 2, Y: 3},
+				To:       generated.EstatePlot{X: 4, Y: 5},
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "PostEstateIdNoFlyZone_Error_Invalid_Plot",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "from": { "x": 0, "y": 1 }, "to": { "x": 2, "y": 3 } }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.NoFlyZone{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdNoFlyZone_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "from": { "x": 1, "y": 1 }, "to": { "x": 2, "y": 3 } }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.NoFlyZone{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PostEstateIdNoFlyZone_Error_Outside_The_Estate",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "from": { "x": 1, "y": 1 }, "to": { "x": 2, "y": 6 } }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.NoFlyZone{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdNoFlyZone_Error_Too_Many_Zones",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "from": { "x": 1, "y": 1 }, "to": { "x": 2, "y": 3 } }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(make([]repository.NoFlyZone, maxNoFlyZones), nil)
+			},
+			response:   generated.NoFlyZone{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdNoFlyZone_Error_Create",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "from": { "x": 1, "y": 1 }, "to": { "x": 2, "y": 3 } }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().CreateNoFlyZone(gomock.Any(), gomock.Any()).Return(repository.NoFlyZone{}, errors.New("error"))
+			},
+			response:   generated.NoFlyZone{},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, fmt.Sprintf("/estate/%s/no-fly-zone", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostEstateIdNoFlyZone(c, tc.pathId))
+
+			var resp generated.NoFlyZone
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdNoFlyZone(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdNoFlyZone_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 10, Length: 5}, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return([]repository.NoFlyZone{
+					{Id: "zone-1", EstateId: "uuid-1", FromX: 2, FromY: 3, ToX: 4, ToY: 5},
+				}, nil)
+			},
+			response: generated.GetNoFlyZonesResponse{
+				NoFlyZones: []generated.NoFlyZone{
+					{
+						Id:       "zone-1",
+						EstateId: "uuid-1",
+						From:     generated.EstatePlot{X: 2, Y: 3},
+						To:       generated.EstatePlot{X: 4, Y: 5},
+					},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdNoFlyZone_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetNoFlyZonesResponse{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/estate/%s/no-fly-zone", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetEstateIdNoFlyZone(c, tc.pathId))
+
+			var resp generated.GetNoFlyZonesResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestDeleteNoFlyZoneId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "DeleteNoFlyZoneId_Success",
+			pathId: "zone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteNoFlyZone(gomock.Any(), "zone-1").Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:   "DeleteNoFlyZoneId_Error_Not_Found",
+			pathId: "zone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteNoFlyZone(gomock.Any(), "zone-1").Return(sql.ErrNoRows)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.DELETE, fmt.Sprintf("/no-fly-zone/%s", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.DeleteNoFlyZoneId(c, tc.pathId))
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
+
+func TestPutEstateIdElevation(t *testing.T) {
+	estate := repository.Estate{
+		Id:     "uuid-1",
+		Width:  3,
+		Length: 2,
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PutEstateIdElevation_Success_Sparse",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "sparse", "base_elevation": 100, "points": [{ "x": 2, "y": 1, "elevation": 104 }, { "x": 3, "y": 2, "elevation": 100 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().ReplaceElevations(gomock.Any(), "uuid-1", 100, []repository.PlotElevation{
+					{EstateId: "uuid-1", X: 2, Y: 1, Elevation: 104},
+				}).Return(nil)
+			},
+			response: generated.PutElevationResponse{
+				BaseElevation: 100,
+				PointCount:    1,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PutEstateIdElevation_Success_Interpolate",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "interpolate", "points": [{ "x": 1, "y": 1, "elevation": 10 }, { "x": 3, "y": 1, "elevation": 20 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().ReplaceElevations(gomock.Any(), "uuid-1", 15, []repository.PlotElevation{
+					{EstateId: "uuid-1", X: 1, Y: 1, Elevation: 10},
+					{EstateId: "uuid-1", X: 3, Y: 1, Elevation: 20},
+					{EstateId: "uuid-1", X: 1, Y: 2, Elevation: 12},
+					{EstateId: "uuid-1", X: 3, Y: 2, Elevation: 18},
+				}).Return(nil)
+			},
+			response: generated.PutElevationResponse{
+				BaseElevation: 15,
+				PointCount:    4,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "sparse", "points": [] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Invalid_Mode",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "contour", "points": [] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Outside_The_Estate",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "sparse", "points": [{ "x": 4, "y": 1, "elevation": 104 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Invalid_Elevation",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "sparse", "points": [{ "x": 1, "y": 1, "elevation": 9001 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Duplicate_Point",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "sparse", "points": [{ "x": 1, "y": 1, "elevation": 10 }, { "x": 1, "y": 1, "elevation": 12 }] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Interpolate_Without_Points",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "interpolate", "points": [] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PutEstateIdElevation_Error_Replace",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "mode": "sparse", "points": [] }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(estate, nil)
+				mockRepo.EXPECT().ReplaceElevations(gomock.Any(), "uuid-1", 0, gomock.Any()).Return(errors.New("error"))
+			},
+			response:   generated.PutElevationResponse{},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.PUT, fmt.Sprintf("/estate/%s/elevation", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PutEstateIdElevation(c, tc.pathId))
+
+			var resp generated.PutElevationResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdElevation(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdElevation_Success",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 3, Length: 2, Elevation: 100}, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return([]repository.PlotElevation{
+					{EstateId: "uuid-1", X: 2, Y: 1, Elevation: 104},
+				}, nil)
+			},
+			response: generated.GetElevationResponse{
+				BaseElevation: 100,
+				Points: []generated.PlotElevation{
+					{X: 2, Y: 1, Elevation: 104},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdElevation_Success_Flat",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 3, Length: 2}, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.GetElevationResponse{
+				Points: []generated.PlotElevation{},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdElevation_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetElevationResponse{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/estate/%s/elevation", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetEstateIdElevation(c, tc.pathId))
+
+			var resp generated.GetElevationResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPostDrone(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "PostDrone_Success",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 12, "climb_rate": 3, "descent_rate": 2, "endurance": 20000 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().CreateDrone(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input repository.Drone) (repository.Drone, error) {
+					assert.Equal(t, droneplan.DefaultClearance, input.Clearance)
+					input.Id = "drone-1"
+					return input, nil
+				})
+			},
+			response: generated.Drone{
+				Id:          "drone-1",
+				Model:       "Survey X4",
+				CruiseSpeed: 12,
+				ClimbRate:   3,
+				DescentRate: 2,
+				Endurance:   20000,
+				Clearance:   1,
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name: "PostDrone_Error_Empty_Model",
+			request: args{
+				payload: `{ "model": " ", "cruise_speed": 12, "climb_rate": 3, "descent_rate": 2, "endurance": 20000 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Drone{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDrone_Error_Invalid_Cruise_Speed",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 0, "climb_rate": 3, "descent_rate": 2, "endurance": 20000 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Drone{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDrone_Error_Invalid_Endurance",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 12, "climb_rate": 3, "descent_rate": 2, "endurance": -1 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Drone{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "PostDrone_Error_Invalid_Clearance",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 12, "climb_rate": 3, "descent_rate": 2, "endurance": 20000, "clearance": 0 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Drone{},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			path := "/drone"
+			method := echo.POST
+			req := httptest.NewRequest(method, path, bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostDrone(c))
+
+			var resp generated.Drone
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetDrone(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "GetDrone_Success",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDrones(gomock.Any()).Return([]repository.Drone{
+					{Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 20000, Clearance: 1},
+				}, nil)
+			},
+			response: generated.GetDronesResponse{
+				Drones: []generated.Drone{
+					{Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 20000, Clearance: 1},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "GetDrone_Success_Without_Drones",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDrones(gomock.Any()).Return(nil, nil)
+			},
+			response: generated.GetDronesResponse{
+				Drones: []generated.Drone{},
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/drone", nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetDrone(c))
+
+			var resp generated.GetDronesResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetDroneId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "GetDroneId_Success",
+			pathId: "drone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "drone-1").Return(repository.Drone{
+					Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 20000, Clearance: 1,
+				}, nil)
+			},
+			response: generated.Drone{
+				Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 20000, Clearance: 1,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetDroneId_Error_Not_Found",
+			pathId: "drone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "drone-1").Return(repository.Drone{}, sql.ErrNoRows)
+			},
+			response:   generated.Drone{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/drone/%s", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetDroneId(c, tc.pathId))
+
+			var resp generated.Drone
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPutDroneId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "PutDroneId_Success",
+			pathId: "drone-1",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 12, "climb_rate": 3, "descent_rate": 2, "endurance": 25000, "clearance": 2 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().UpdateDrone(gomock.Any(), repository.Drone{
+					Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 25000, Clearance: 2,
+				}).Return(repository.Drone{
+					Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 25000, Clearance: 2,
+				}, nil)
+			},
+			response: generated.Drone{
+				Id: "drone-1", Model: "Survey X4", CruiseSpeed: 12, ClimbRate: 3, DescentRate: 2, Endurance: 25000, Clearance: 2,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PutDroneId_Error_Not_Found",
+			pathId: "drone-1",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 12, "climb_rate": 3, "descent_rate": 2, "endurance": 25000 }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().UpdateDrone(gomock.Any(), gomock.Any()).Return(repository.Drone{}, sql.ErrNoRows)
+			},
+			response:   generated.Drone{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PutDroneId_Error_Invalid_Climb_Rate",
+			pathId: "drone-1",
+			request: args{
+				payload: `{ "model": "Survey X4", "cruise_speed": 12, "climb_rate": -3, "descent_rate": 2, "endurance": 25000 }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Drone{},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.PUT, fmt.Sprintf("/drone/%s", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PutDroneId(c, tc.pathId))
+
+			var resp generated.Drone
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestDeleteDroneId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:   "DeleteDroneId_Success",
+			pathId: "drone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteDrone(gomock.Any(), "drone-1").Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:   "DeleteDroneId_Error_Not_Found",
+			pathId: "drone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteDrone(gomock.Any(), "drone-1").Return(sql.ErrNoRows)
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "DeleteDroneId_Error_In_Use",
+			pathId: "drone-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().DeleteDrone(gomock.Any(), "drone-1").Return(repository.ErrDroneInUse)
+			},
+			response:   conflict(generated.DroneInUse, "Drone has missions").response(),
+			statusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.DELETE, fmt.Sprintf("/drone/%s", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.DeleteDroneId(c, tc.pathId))
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.response != nil {
+				var resp generated.ErrorResponse
+				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+				assert.Equal(t, tc.response, resp)
+			}
+		})
+	}
+}
+
+func TestPostEstateIdMission(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	drone := repository.Drone{
+		Id:          "123e4567-e89b-12d3-a456-426614174000",
+		Model:       "Survey X4",
+		CruiseSpeed: 10,
+		ClimbRate:   1,
+		DescentRate: 2,
+		Endurance:   1000,
+		Clearance:   1,
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PostEstateIdMission_Success",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "drone_id": "123e4567-e89b-12d3-a456-426614174000", "scheduled_at": "2024-05-01T08:00:00Z" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(drone, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+					{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+					{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+					{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+				}, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().CreateMission(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input repository.Mission) (repository.Mission, error) {
+					input.Id = "mission-1"
+					return input, nil
+				})
+			},
+			response: generated.Mission{
+				Id:              "mission-1",
+				EstateId:        "uuid-1",
+				DroneId:         "123e4567-e89b-12d3-a456-426614174000",
+				PlannedDistance: 54,
+				TreeCount:       3,
+				ScheduledAt:     scheduledAt,
+				Status:          generated.MissionStatus("planned"),
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:   "PostEstateIdMission_Error_Malformed_Drone_Id",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "drone_id": "drone-1", "scheduled_at": "2024-05-01T08:00:00Z" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Mission{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdMission_Error_Missing_Scheduled_At",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "drone_id": "123e4567-e89b-12d3-a456-426614174000" }`,
+			},
+			mockFunc:   func() {},
+			response:   generated.Mission{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostEstateIdMission_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "drone_id": "123e4567-e89b-12d3-a456-426614174000", "scheduled_at": "2024-05-01T08:00:00Z" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.Mission{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "PostEstateIdMission_Error_Drone_Not_Found",
+			pathId: "uuid-1",
+			request: args{
+				payload: `{ "drone_id": "123e4567-e89b-12d3-a456-426614174000", "scheduled_at": "2024-05-01T08:00:00Z" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+					Id:     "uuid-1",
+					Width:  5,
+					Length: 1,
+				}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(repository.Drone{}, sql.ErrNoRows)
+			},
+			response:   generated.Mission{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, fmt.Sprintf("/estate/%s/mission", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostEstateIdMission(c, tc.pathId))
+
+			var resp generated.Mission
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetEstateIdMission(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	status := generated.MissionStatus("completed")
+
+	testCases := []testCase{
+		{
+			name:   "GetEstateIdMission_Success_With_Filter",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdMissionParams{
+				Status:        &status,
+				ScheduledFrom: &scheduledAt,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 5, Length: 1}, nil)
+				mockRepo.EXPECT().GetMissions(gomock.Any(), repository.MissionFilter{
+					EstateId:      "uuid-1",
+					Status:        stringPtr("completed"),
+					ScheduledFrom: &scheduledAt,
+				}).Return([]repository.Mission{
+					{
+						Id:              "mission-1",
+						EstateId:        "uuid-1",
+						DroneId:         "drone-1",
+						PlannedDistance: 54,
+						TreeCount:       3,
+						ScheduledAt:     scheduledAt,
+						Status:          repository.MissionStatusCompleted,
+						ActualDistance:  intPtr(60),
+					},
+				}, nil)
+			},
+			response: generated.GetMissionsResponse{
+				Missions: []generated.Mission{
+					{
+						Id:              "mission-1",
+						EstateId:        "uuid-1",
+						DroneId:         "drone-1",
+						PlannedDistance: 54,
+						TreeCount:       3,
+						ScheduledAt:     scheduledAt,
+						Status:          status,
+						ActualDistance:  intPtr(60),
+					},
+				},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdMission_Success_Without_Missions",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdMissionParams{},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 5, Length: 1}, nil)
+				mockRepo.EXPECT().GetMissions(gomock.Any(), repository.MissionFilter{EstateId: "uuid-1"}).Return(nil, nil)
+			},
+			response: generated.GetMissionsResponse{
+				Missions: []generated.Mission{},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetEstateIdMission_Error_Invalid_Status",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdMissionParams{
+				Status: func() *generated.MissionStatus { s := generated.MissionStatus("landed"); return &s }(),
+			},
+			mockFunc:   func() {},
+			response:   generated.GetMissionsResponse{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "GetEstateIdMission_Error_Estate_Not_Found",
+			pathId: "uuid-1",
+			params: generated.GetEstateIdMissionParams{},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{}, sql.ErrNoRows)
+			},
+			response:   generated.GetMissionsResponse{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/estate/%s/mission", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetEstateIdMission(c, tc.pathId, tc.params.(generated.GetEstateIdMissionParams)))
+
+			var resp generated.GetMissionsResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPatchMissionId(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	mission := func(status string) repository.Mission {
+		return repository.Mission{
+			Id:              "mission-1",
+			EstateId:        "uuid-1",
+			DroneId:         "drone-1",
+			PlannedDistance: 54,
+			TreeCount:       3,
+			ScheduledAt:     scheduledAt,
+			Status:          status,
+		}
+	}
+
+	testCases := []testCase{
+		{
+			name:   "PatchMissionId_Success_Take_Off",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "in-flight" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission(repository.MissionStatusPlanned), nil)
+				mockRepo.EXPECT().UpdateMission(gomock.Any(), mission(repository.MissionStatusInFlight), repository.MissionStatusPlanned).Return(mission(repository.MissionStatusInFlight), nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "PatchMissionId_Success_Complete_With_Actual_Distance",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "completed", "actual_distance": 60 }`,
+			},
+			mockFunc: func() {
+				completed := mission(repository.MissionStatusCompleted)
+				completed.ActualDistance = intPtr(60)
+
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission(repository.MissionStatusInFlight), nil)
+				mockRepo.EXPECT().UpdateMission(gomock.Any(), completed, repository.MissionStatusInFlight).Return(completed, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			// Another request aborted the mission after it was read.
+			name:   "PatchMissionId_Error_Moved_Meanwhile",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "completed" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission(repository.MissionStatusInFlight), nil)
+				mockRepo.EXPECT().UpdateMission(gomock.Any(), mission(repository.MissionStatusCompleted), repository.MissionStatusInFlight).Return(repository.Mission{}, repository.ErrMissionMoved)
+			},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:   "PatchMissionId_Error_Completed_Mission_Cannot_Take_Off",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "in-flight" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission(repository.MissionStatusCompleted), nil)
+			},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:   "PatchMissionId_Error_Actual_Distance_While_Planned",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "planned", "actual_distance": 60 }`,
+			},
+			mockFunc:   func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PatchMissionId_Error_Invalid_Status",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "landed" }`,
+			},
+			mockFunc:   func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PatchMissionId_Error_Not_Found",
+			pathId: "mission-1",
+			request: args{
+				payload: `{ "status": "aborted" }`,
+			},
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(repository.Mission{}, sql.ErrNoRows)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.PATCH, fmt.Sprintf("/mission/%s", tc.pathId), bytes.NewReader([]byte(tc.request.payload)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PatchMissionId(c, tc.pathId))
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
+
+func TestGetMissionId(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name:   "GetMissionId_Success",
+			pathId: "mission-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(repository.Mission{
+					Id:              "mission-1",
+					EstateId:        "uuid-1",
+					DroneId:         "drone-1",
+					PlannedDistance: 54,
+					TreeCount:       3,
+					ScheduledAt:     scheduledAt,
+					Status:          repository.MissionStatusPlanned,
+				}, nil)
+			},
+			response: generated.Mission{
+				Id:              "mission-1",
+				EstateId:        "uuid-1",
+				DroneId:         "drone-1",
+				PlannedDistance: 54,
+				TreeCount:       3,
+				ScheduledAt:     scheduledAt,
+				Status:          generated.MissionStatus("planned"),
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetMissionId_Error_Not_Found",
+			pathId: "mission-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(repository.Mission{}, sql.ErrNoRows)
+			},
+			response:   generated.Mission{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/mission/%s", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetMissionId(c, tc.pathId))
+
+			var resp generated.Mission
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestPostMissionIdTelemetry(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	mission := repository.Mission{
+		Id:              "mission-1",
+		EstateId:        "uuid-1",
+		DroneId:         "drone-1",
+		PlannedDistance: 54,
+		TreeCount:       3,
+		ScheduledAt:     scheduledAt,
+		Status:          repository.MissionStatusCompleted,
+		LayoutDigest: layoutDigest(droneplan.Options{
+			Width:  5,
+			Length: 1,
+			Trees: []droneplan.Tree{
+				{X: 2, Y: 1, Height: 5},
+				{X: 3, Y: 1, Height: 3},
+				{X: 4, Y: 1, Height: 4},
+			},
+			Clearance: 1,
+		}),
+	}
+	missionPlan := func() {
+		mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission, nil)
+		mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{
+			Id:     "uuid-1",
+			Width:  5,
+			Length: 1,
+		}, nil)
+		mockRepo.EXPECT().GetDroneById(gomock.Any(), "drone-1").Return(repository.Drone{
+			Id:          "drone-1",
+			Model:       "Survey X4",
+			CruiseSpeed: 10,
+			ClimbRate:   1,
+			DescentRate: 1,
+			Endurance:   1000,
+			Clearance:   1,
+		}, nil)
+		mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return([]repository.EstateTree{
+			{Id: "uuid-1", EstateId: "uuid-1", X: 2, Y: 1, Height: 5},
+			{Id: "uuid-2", EstateId: "uuid-1", X: 3, Y: 1, Height: 3},
+			{Id: "uuid-3", EstateId: "uuid-1", X: 4, Y: 1, Height: 4},
+		}, nil)
+		mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+		mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+		mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+	}
+
+	testCases := []struct {
+		name        string
+		contentType string
+		payload     string
+		mockFunc    func()
+		response    generated.TelemetryReport
+		statusCode  int
+	}{
+		{
+			name:        "PostMissionIdTelemetry_Success_CSV",
+			contentType: "text/csv",
+			payload: "timestamp,x,y,altitude\n" +
+				"2024-05-01T08:00:00Z,1,1,0\n" +
+				"2024-05-01T08:00:01Z,1,1,6\n" +
+				"2024-05-01T08:00:02Z,2,1,6\n" +
+				"2024-05-01T08:00:03Z,3,1,6\n" +
+				"2024-05-01T08:00:04Z,3,1,3\n" +
+				"2024-05-01T08:00:05Z,3,1,0\n",
+			mockFunc: func() {
+				missionPlan()
+				mockRepo.EXPECT().ReplaceTelemetry(gomock.Any(), "mission-1", gomock.Len(6)).Return(nil)
+			},
+			response: generated.TelemetryReport{
+				SampleCount:            6,
+				PlannedDistance:        54,
+				ActualDistance:         32,
+				ExtraDistance:          -22,
+				MissedPlotCount:        2,
+				MissedPlots:            []generated.EstatePlot{{X: 4, Y: 1}, {X: 5, Y: 1}},
+				AltitudeViolationCount: 2,
+				AltitudeViolations: []generated.AltitudeViolation{
+					{Timestamp: scheduledAt.Add(4 * time.Second), X: 3, Y: 1, Altitude: 3, RequiredAltitude: 4},
+					{Timestamp: scheduledAt.Add(5 * time.Second), X: 3, Y: 1, Altitude: 0, RequiredAltitude: 4},
+				},
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:        "PostMissionIdTelemetry_Success_JSON_Lines_Out_Of_Order",
+			contentType: "application/x-ndjson",
+			payload: `{"timestamp": "2024-05-01T08:00:01Z", "x": 1, "y": 1, "altitude": 6}` + "\n" +
+				`{"timestamp": "2024-05-01T08:00:00Z", "x": 1, "y": 1, "altitude": 0}` + "\n",
+			mockFunc: func() {
+				missionPlan()
+				mockRepo.EXPECT().ReplaceTelemetry(gomock.Any(), "mission-1", []repository.TelemetrySample{
+					{RecordedAt: scheduledAt, X: 1, Y: 1, Altitude: 0},
+					{RecordedAt: scheduledAt.Add(time.Second), X: 1, Y: 1, Altitude: 6},
+				}).Return(nil)
+			},
+			response: generated.TelemetryReport{
+				SampleCount:        2,
+				PlannedDistance:    54,
+				ActualDistance:     6,
+				ExtraDistance:      -48,
+				MissedPlotCount:    4,
+				MissedPlots:        []generated.EstatePlot{{X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 5, Y: 1}},
+				AltitudeViolations: []generated.AltitudeViolation{},
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:        "PostMissionIdTelemetry_Error_Invalid_Line",
+			contentType: "text/csv",
+			payload:     "2024-05-01T08:00:00Z,1,1,0\n2024-05-01T08:00:01Z,one,1,6\n",
+			mockFunc:    func() {},
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "PostMissionIdTelemetry_Error_Missing_Field",
+			contentType: "application/x-ndjson",
+			payload:     `{"timestamp": "2024-05-01T08:00:00Z", "x": 1, "y": 1}`,
+			mockFunc:    func() {},
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "PostMissionIdTelemetry_Error_Empty_Log",
+			contentType: "text/csv",
+			payload:     "timestamp,x,y,altitude\n",
+			mockFunc:    func() {},
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "PostMissionIdTelemetry_Error_Unsupported_Media_Type",
+			contentType: "application/xml",
+			payload:     "<log/>",
+			mockFunc:    func() {},
+			statusCode:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "PostMissionIdTelemetry_Error_Mission_Not_Found",
+			contentType: "text/csv",
+			payload:     "2024-05-01T08:00:00Z,1,1,0\n",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(repository.Mission{}, sql.ErrNoRows)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, "/mission/mission-1/telemetry", bytes.NewReader([]byte(tc.payload)))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.PostMissionIdTelemetry(c, "mission-1"))
+
+			var resp generated.TelemetryReport
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestGetMissionIdTelemetryReport(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	mission := repository.Mission{
+		Id:              "mission-1",
+		EstateId:        "uuid-1",
+		DroneId:         "drone-1",
+		PlannedDistance: 2,
+		TreeCount:       0,
+		ScheduledAt:     scheduledAt,
+		Status:          repository.MissionStatusCompleted,
+		LayoutDigest:    layoutDigest(droneplan.Options{Width: 1, Length: 1, Clearance: 1}),
+	}
+	// The tree of the estate was planted after the mission was scheduled.
+	stale := mission
+	stale.LayoutDigest = layoutDigest(droneplan.Options{Width: 1, Length: 1, Clearance: 1, Trees: []droneplan.Tree{{X: 1, Y: 1, Height: 1}}})
+
+	testCases := []testCase{
+		{
+			name:   "GetMissionIdTelemetryReport_Success",
+			pathId: "mission-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission, nil)
+				mockRepo.EXPECT().GetTelemetryByMissionId(gomock.Any(), "mission-1").Return([]repository.TelemetrySample{
+					{MissionId: "mission-1", RecordedAt: scheduledAt, X: 1, Y: 1, Altitude: 0},
+					{MissionId: "mission-1", RecordedAt: scheduledAt.Add(time.Second), X: 1, Y: 1, Altitude: 1},
+					{MissionId: "mission-1", RecordedAt: scheduledAt.Add(2 * time.Second), X: 1, Y: 1, Altitude: 0},
+				}, nil)
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 1, Length: 1}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "drone-1").Return(repository.Drone{Id: "drone-1", Clearance: 1}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.TelemetryReport{
+				SampleCount:        3,
+				PlannedDistance:    2,
+				ActualDistance:     2,
+				MissedPlots:        []generated.EstatePlot{},
+				AltitudeViolations: []generated.AltitudeViolation{},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetMissionIdTelemetryReport_Success_Layout_Changed",
+			pathId: "mission-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(stale, nil)
+				mockRepo.EXPECT().GetTelemetryByMissionId(gomock.Any(), "mission-1").Return([]repository.TelemetrySample{
+					{MissionId: "mission-1", RecordedAt: scheduledAt, X: 1, Y: 1, Altitude: 0},
+					{MissionId: "mission-1", RecordedAt: scheduledAt.Add(time.Second), X: 1, Y: 1, Altitude: 1},
+					{MissionId: "mission-1", RecordedAt: scheduledAt.Add(2 * time.Second), X: 1, Y: 1, Altitude: 0},
+				}, nil)
+				mockRepo.EXPECT().GetEstateById(gomock.Any(), "uuid-1").Return(repository.Estate{Id: "uuid-1", Width: 1, Length: 1}, nil)
+				mockRepo.EXPECT().GetDroneById(gomock.Any(), "drone-1").Return(repository.Drone{Id: "drone-1", Clearance: 1}, nil)
+				mockRepo.EXPECT().GetTreesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetObstaclesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetNoFlyZonesByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+				mockRepo.EXPECT().GetElevationsByEstateId(gomock.Any(), "uuid-1").Return(nil, nil)
+			},
+			response: generated.TelemetryReport{
+				SampleCount:        3,
+				PlannedDistance:    2,
+				ActualDistance:     2,
+				MissedPlots:        []generated.EstatePlot{},
+				AltitudeViolations: []generated.AltitudeViolation{},
+				LayoutChanged:      true,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:   "GetMissionIdTelemetryReport_Error_No_Flight_Log",
+			pathId: "mission-1",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMissionById(gomock.Any(), "mission-1").Return(mission, nil)
+				mockRepo.EXPECT().GetTelemetryByMissionId(gomock.Any(), "mission-1").Return(nil, nil)
+			},
+			response:   generated.TelemetryReport{},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialize(t)
+
+			tc.mockFunc()
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, fmt.Sprintf("/mission/%s/telemetry/report", tc.pathId), nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			handle(c, server.GetMissionIdTelemetryReport(c, tc.pathId))
+
+			var resp generated.TelemetryReport
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.response, resp)
+		})
+	}
+}
+
+func TestLayoutDigest(t *testing.T) {
+	opts := droneplan.Options{
+		Width:  3,
+		Length: 2,
+		Trees: []droneplan.Tree{
+			{X: 2, Y: 1, Height: 5},
+			{X: 1, Y: 2, Height: 3},
+		},
+		Obstacles:  []droneplan.Obstacle{{X: 3, Y: 2, Height: 10}},
+		NoFlyZones: []droneplan.Area{{From: droneplan.Plot{X: 3, Y: 1}, To: droneplan.Plot{X: 3, Y: 1}}},
+		Clearance:  1,
+	}
+	digest := layoutDigest(opts)
+	assert.Len(t, digest, 64)
+
+	reordered := opts
+	reordered.Trees = []droneplan.Tree{opts.Trees[1], opts.Trees[0]}
+	reordered.Elevations = []droneplan.Elevation{}
+	assert.Equal(t, digest, layoutDigest(reordered))
+	assert.Equal(t, droneplan.Tree{X: 2, Y: 1, Height: 5}, opts.Trees[0])
+
+	taller := opts
+	taller.Trees = []droneplan.Tree{{X: 2, Y: 1, Height: 6}, opts.Trees[1]}
+	assert.NotEqual(t, digest, layoutDigest(taller))
+
+	clearer := opts
+	clearer.Clearance = 2
+	assert.NotEqual(t, digest, layoutDigest(clearer))
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		err        error
+		response   generated.ErrorResponse
+		statusCode int
+	}{
+		{
+			name:       "HTTPErrorHandler_API_Error",
+			err:        invalidField("width", "Invalid Width"),
+			response:   invalidField("width", "Invalid Width").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "HTTPErrorHandler_No_Rows",
+			err:        sql.ErrNoRows,
+			response:   notFound(generated.NotFound, "Not found").response(),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "HTTPErrorHandler_Plot_Occupied",
+			err:        fmt.Errorf("create tree: %w", repository.ErrPlotOccupied),
+			response:   conflict(generated.PlotOccupied, "Plot already holds a tree or an obstacle").response(),
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "HTTPErrorHandler_Malformed_Id",
+			err:        &pq.Error{Code: "22P02", Message: `invalid input syntax for type uuid: "uuid-1"`},
+			response:   invalidParameter("id", "Invalid Id").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "HTTPErrorHandler_Echo_Bad_Parameter",
+			err:        echo.NewHTTPError(http.StatusBadRequest, "Invalid format for parameter id"),
+			response:   newError(http.StatusBadRequest, generated.InvalidParameter, "Invalid format for parameter id").response(),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "HTTPErrorHandler_Echo_Method_Not_Allowed",
+			err:        echo.ErrMethodNotAllowed,
+			response:   newError(http.StatusMethodNotAllowed, generated.MethodNotAllowed, "Method Not Allowed").response(),
+			statusCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "HTTPErrorHandler_Database_Error",
+			err:        errors.New(`pq: relation "estates" does not exist`),
+			response:   newError(http.StatusInternalServerError, generated.InternalError, "Internal server error").response(),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/estate", nil)
+			rr := httptest.NewRecorder()
+			c := e.NewContext(req, rr)
+			HTTPErrorHandler(tc.err, c)
+
+			var resp generated.ErrorResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 			assert.Equal(t, tc.statusCode, rr.Code)
 			assert.Equal(t, tc.response, resp)
 		})
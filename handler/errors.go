@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// apiError is an error reported to the client as it is: the status, a
+// machine-readable code, a message, and optionally the field at fault and
+// more details. Handlers return it and HTTPErrorHandler writes it out.
+type apiError struct {
+	status  int
+	code    generated.ErrorCode
+	message string
+	field   string
+	details map[string]interface{}
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// withField names the request field or parameter at fault.
+func (e *apiError) withField(field string) *apiError {
+	e.field = field
+	return e
+}
+
+// withDetail adds a fact about the error, such as the limit exceeded.
+func (e *apiError) withDetail(key string, value interface{}) *apiError {
+	if e.details == nil {
+		e.details = make(map[string]interface{})
+	}
+	e.details[key] = value
+	return e
+}
+
+// response returns the error as the client sees it.
+func (e *apiError) response() generated.ErrorResponse {
+	response := generated.ErrorResponse{
+		Code:    e.code,
+		Message: e.message,
+	}
+	if e.field != "" {
+		response.Field = &e.field
+	}
+	if e.details != nil {
+		response.Details = &e.details
+	}
+	return response
+}
+
+func newError(status int, code generated.ErrorCode, message string) *apiError {
+	return &apiError{
+		status:  status,
+		code:    code,
+		message: message,
+	}
+}
+
+// invalidBody reports a body that cannot be bound to the request.
+func invalidBody() *apiError {
+	return newError(http.StatusBadRequest, generated.InvalidRequestBody, "Invalid Request Body")
+}
+
+// invalidField reports an invalid field of the request body.
+func invalidField(field, message string) *apiError {
+	return newError(http.StatusBadRequest, generated.ValidationFailed, message).withField(field)
+}
+
+// invalidParameter reports an invalid path or query parameter.
+func invalidParameter(name, message string) *apiError {
+	return newError(http.StatusBadRequest, generated.InvalidParameter, message).withField(name)
+}
+
+// badRequest reports a request the API refuses for the given reason.
+func badRequest(code generated.ErrorCode, message string) *apiError {
+	return newError(http.StatusBadRequest, code, message)
+}
+
+// limitExceeded reports a request asking for more than max of something.
+func limitExceeded(field, message string, max int) *apiError {
+	return newError(http.StatusBadRequest, generated.LimitExceeded, message).withField(field).withDetail("max", max)
+}
+
+func notFound(code generated.ErrorCode, message string) *apiError {
+	return newError(http.StatusNotFound, code, message)
+}
+
+func conflict(code generated.ErrorCode, message string) *apiError {
+	return newError(http.StatusConflict, code, message)
+}
+
+// HTTPErrorHandler writes the error a handler returns as an ErrorResponse.
+// The errors of the API, of the repository and of echo are mapped to their
+// status and code. Any other error is an internal one: it is logged and the
+// client only learns that something went wrong, never the cause, so that
+// no database detail reaches it.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	e := toAPIError(err)
+	if e.status >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(e.status)
+	} else {
+		err = c.JSON(e.status, e.response())
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// toAPIError maps an error to the one reported to the client.
+func toAPIError(err error) *apiError {
+	var e *apiError
+	if errors.As(err, &e) {
+		return e
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return notFound(generated.NotFound, "Not found")
+	case errors.Is(err, repository.ErrPlotOccupied):
+		return conflict(generated.PlotOccupied, "Plot already holds a tree or an obstacle")
+	case errors.Is(err, repository.ErrOutsideEstate):
+		return badRequest(generated.OutOfBounds, "Plot is outside the estate")
+	case errors.Is(err, repository.ErrOutsideBounds):
+		return conflict(generated.EstateTooSmall, "Trees or obstacles lie outside the new size of the estate")
+	case errors.Is(err, repository.ErrMissionMoved):
+		return conflict(generated.InvalidTransition, "Mission moved to another status meanwhile")
+	case errors.Is(err, repository.ErrDroneInUse):
+		return conflict(generated.DroneInUse, "Drone has missions")
+	case repository.IsInvalidId(err):
+		return invalidParameter("id", "Invalid Id")
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) && he.Code < http.StatusInternalServerError {
+		message := http.StatusText(he.Code)
+		if m, ok := he.Message.(string); ok {
+			message = m
+		} else if m, ok := he.Message.(error); ok {
+			message = m.Error()
+		}
+
+		switch he.Code {
+		case http.StatusBadRequest:
+			// The generated wrappers reject the parameters they cannot bind
+			// this way.
+			return newError(he.Code, generated.InvalidParameter, message)
+		case http.StatusNotFound:
+			return newError(he.Code, generated.NotFound, message)
+		case http.StatusMethodNotAllowed:
+			return newError(he.Code, generated.MethodNotAllowed, message)
+		case http.StatusUnsupportedMediaType:
+			return newError(he.Code, generated.UnsupportedMediaType, message)
+		}
+		return newError(he.Code, generated.BadRequest, message)
+	}
+
+	return newError(http.StatusInternalServerError, generated.InternalError, "Internal server error")
+}
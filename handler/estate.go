@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// maxEstateSide is the most plots an estate may stretch along either side.
+const maxEstateSide = 50000
+
+// validateEstate checks the size, location and ceiling of an estate.
+func validateEstate(estate repository.Estate) error {
+	if estate.Width <= 0 || estate.Width > maxEstateSide {
+		return invalidField("width", "Invalid Width")
+	}
+
+	if estate.Length <= 0 || estate.Length > maxEstateSide {
+		return invalidField("length", "Invalid Length")
+	}
+
+	if (estate.Latitude == nil) != (estate.Longitude == nil) {
+		return invalidField("longitude", "Latitude and Longitude must be set together")
+	}
+
+	if estate.Latitude != nil && (*estate.Latitude < -90 || *estate.Latitude > 90) {
+		return invalidField("latitude", "Invalid Latitude")
+	}
+
+	if estate.Longitude != nil && (*estate.Longitude < -180 || *estate.Longitude > 180) {
+		return invalidField("longitude", "Invalid Longitude")
+	}
+
+	if estate.Bearing < 0 || estate.Bearing >= 360 {
+		return invalidField("bearing", "Invalid Bearing")
+	}
+
+	if estate.MaxAltitude != nil && (*estate.MaxAltitude < 1 || *estate.MaxAltitude > maxCeiling) {
+		return invalidField("max_altitude", "Invalid Max Altitude")
+	}
+
+	return nil
+}
+
+// patchEstate applies the fields set in the request to the estate, removes
+// the location or the ceiling it asks to clear, and checks the result.
+func patchEstate(estate repository.Estate, req generated.UpdateEstateRequest) (repository.Estate, error) {
+	if req.Width != nil {
+		estate.Width = *req.Width
+	}
+	if req.Length != nil {
+		estate.Length = *req.Length
+	}
+	if req.ClearLocation != nil && *req.ClearLocation {
+		if req.Latitude != nil || req.Longitude != nil {
+			return estate, invalidField("clear_location", "Location cannot be set and cleared together")
+		}
+		estate.Latitude, estate.Longitude = nil, nil
+	}
+	if req.Latitude != nil {
+		estate.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		estate.Longitude = req.Longitude
+	}
+	if req.Bearing != nil {
+		estate.Bearing = *req.Bearing
+	}
+	if req.ClearMaxAltitude != nil && *req.ClearMaxAltitude {
+		if req.MaxAltitude != nil {
+			return estate, invalidField("clear_max_altitude", "Max Altitude cannot be set and cleared together")
+		}
+		estate.MaxAltitude = nil
+	}
+	if req.MaxAltitude != nil {
+		estate.MaxAltitude = req.MaxAltitude
+	}
+
+	return estate, validateEstate(estate)
+}
+
+func estateResponse(estate repository.Estate) generated.Estate {
+	return generated.Estate{
+		Id:          estate.Id,
+		Width:       estate.Width,
+		Length:      estate.Length,
+		Latitude:    estate.Latitude,
+		Longitude:   estate.Longitude,
+		Bearing:     estate.Bearing,
+		Elevation:   estate.Elevation,
+		MaxAltitude: estate.MaxAltitude,
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/geo"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// estateReference returns where the estate lies on the globe, or nil when
+// the estate has no location.
+func estateReference(estate repository.Estate) *geo.Reference {
+	if estate.Latitude == nil || estate.Longitude == nil {
+		return nil
+	}
+
+	return &geo.Reference{
+		Latitude:  *estate.Latitude,
+		Longitude: *estate.Longitude,
+		Bearing:   estate.Bearing,
+	}
+}
+
+// treeFeatures places every tree of the estate on the globe, at the top of
+// the tree.
+func treeFeatures(reference geo.Reference, trees []repository.EstateTree) []geo.Feature {
+	features := make([]geo.Feature, 0, len(trees))
+	for _, tree := range trees {
+		top := reference.Plot(tree.X, tree.Y, droneplan.PlotSize, float64(tree.Height))
+		features = append(features, geo.Feature{
+			Name: fmt.Sprintf("Tree (%d, %d)", tree.X, tree.Y),
+			Properties: map[string]interface{}{
+				"id":     tree.Id,
+				"x":      tree.X,
+				"y":      tree.Y,
+				"height": tree.Height,
+			},
+			Point: &top,
+		})
+	}
+
+	return features
+}
+
+// exportFeatures sends the features as a KML or GeoJSON file download.
+func exportFeatures(c echo.Context, format, name, filename string, features []geo.Feature) error {
+	var (
+		body      []byte
+		err       error
+		mediaType string
+	)
+
+	switch format {
+	case "kml":
+		body, err = geo.KML(name, features)
+		mediaType = geo.MIMEKML
+	case "geojson":
+		body, err = geo.GeoJSON(features)
+		mediaType = geo.MIMEGeoJSON
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	return c.Blob(http.StatusOK, mediaType, body)
+}
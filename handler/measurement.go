@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// hoursPerYear is the hours of a year of 365.25 days, the year the growth
+// rates are given in.
+const hoursPerYear = 365.25 * 24
+
+// validMeasurementSource reports whether the source is one a measurement
+// may come from.
+func validMeasurementSource(source string) bool {
+	switch source {
+	case repository.MeasurementSourceManual,
+		repository.MeasurementSourceDrone:
+		return true
+	}
+	return false
+}
+
+// treeGrowthRate returns the metres a year a tree grew from its first
+// measurement to its latest, or nil when the measurements, oldest first,
+// were not taken at two times.
+func treeGrowthRate(measurements []repository.TreeMeasurement) *float64 {
+	if len(measurements) < 2 {
+		return nil
+	}
+
+	first, latest := measurements[0], measurements[len(measurements)-1]
+	span := latest.MeasuredAt.Sub(first.MeasuredAt)
+	if span <= 0 {
+		return nil
+	}
+
+	rate := float64(latest.Height-first.Height) / (span.Hours() / hoursPerYear)
+	return &rate
+}
+
+// measurementResponse converts a stored measurement to its API
+// representation.
+func measurementResponse(measurement repository.TreeMeasurement) generated.TreeMeasurement {
+	return generated.TreeMeasurement{
+		Height:     measurement.Height,
+		MeasuredAt: measurement.MeasuredAt,
+		Source:     generated.MeasurementSource(measurement.Source),
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// missionTransitions lists the statuses a mission can move to from each
+// status. Completed and aborted missions are final.
+var missionTransitions = map[string][]string{
+	repository.MissionStatusPlanned:  {repository.MissionStatusInFlight, repository.MissionStatusAborted},
+	repository.MissionStatusInFlight: {repository.MissionStatusCompleted, repository.MissionStatusAborted},
+}
+
+// validMissionStatus reports whether the status is one of the mission
+// statuses.
+func validMissionStatus(status string) bool {
+	switch status {
+	case repository.MissionStatusPlanned,
+		repository.MissionStatusInFlight,
+		repository.MissionStatusCompleted,
+		repository.MissionStatusAborted:
+		return true
+	}
+	return false
+}
+
+// canMoveMission reports whether a mission can move from one status to
+// another. Staying in the same status is always allowed.
+func canMoveMission(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	for _, next := range missionTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// missionResponse converts a stored mission to its API representation.
+func missionResponse(mission repository.Mission) generated.Mission {
+	return generated.Mission{
+		Id:              mission.Id,
+		EstateId:        mission.EstateId,
+		DroneId:         mission.DroneId,
+		PlannedDistance: mission.PlannedDistance,
+		TreeCount:       mission.TreeCount,
+		ScheduledAt:     mission.ScheduledAt,
+		Status:          generated.MissionStatus(mission.Status),
+		ActualDistance:  mission.ActualDistance,
+	}
+}
+
+// layoutDigest returns a digest of the options a drone plan is built from.
+// A mission keeps the digest of the plan it was scheduled with, so that its
+// telemetry report can tell whether the plan built now is still that one.
+// The digest does not depend on the order the layout was loaded in.
+func layoutDigest(opts droneplan.Options) string {
+	opts.Trees = append([]droneplan.Tree(nil), opts.Trees...)
+	sort.Slice(opts.Trees, func(i, j int) bool {
+		return plotBefore(opts.Trees[i].X, opts.Trees[i].Y, opts.Trees[j].X, opts.Trees[j].Y)
+	})
+	opts.Obstacles = append([]droneplan.Obstacle(nil), opts.Obstacles...)
+	sort.Slice(opts.Obstacles, func(i, j int) bool {
+		return plotBefore(opts.Obstacles[i].X, opts.Obstacles[i].Y, opts.Obstacles[j].X, opts.Obstacles[j].Y)
+	})
+	opts.Elevations = append([]droneplan.Elevation(nil), opts.Elevations...)
+	sort.Slice(opts.Elevations, func(i, j int) bool {
+		return plotBefore(opts.Elevations[i].X, opts.Elevations[i].Y, opts.Elevations[j].X, opts.Elevations[j].Y)
+	})
+	opts.NoFlyZones = append([]droneplan.Area(nil), opts.NoFlyZones...)
+	sort.Slice(opts.NoFlyZones, func(i, j int) bool {
+		a, b := opts.NoFlyZones[i], opts.NoFlyZones[j]
+		if a.From != b.From {
+			return plotBefore(a.From.X, a.From.Y, b.From.X, b.From.Y)
+		}
+		return plotBefore(a.To.X, a.To.Y, b.To.X, b.To.Y)
+	})
+
+	data, _ := json.Marshal(opts)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// plotBefore reports whether the plot at x1, y1 comes before the plot at
+// x2, y2, row by row.
+func plotBefore(x1, y1, x2, y2 int) bool {
+	if y1 != y2 {
+		return y1 < y2
+	}
+	return x1 < x2
+}
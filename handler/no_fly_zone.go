@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// maxNoFlyZones is the most no-fly zones one estate may have.
+const maxNoFlyZones = 20
+
+// newNoFlyZone builds the no-fly zone to store from its request, with from
+// at the south-west corner and to at the north-east corner.
+func newNoFlyZone(id, estateId string, req generated.CreateNoFlyZoneRequest) repository.NoFlyZone {
+	zone := repository.NoFlyZone{
+		Id:       id,
+		EstateId: estateId,
+		FromX:    req.From.X,
+		FromY:    req.From.Y,
+		ToX:      req.To.X,
+		ToY:      req.To.Y,
+	}
+	if zone.FromX > zone.ToX {
+		zone.FromX, zone.ToX = zone.ToX, zone.FromX
+	}
+	if zone.FromY > zone.ToY {
+		zone.FromY, zone.ToY = zone.ToY, zone.FromY
+	}
+	return zone
+}
+
+// noFlyZoneResponse converts a stored no-fly zone to its API representation.
+func noFlyZoneResponse(zone repository.NoFlyZone) generated.NoFlyZone {
+	return generated.NoFlyZone{
+		Id:       zone.Id,
+		EstateId: zone.EstateId,
+		From:     generated.EstatePlot{X: zone.FromX, Y: zone.FromY},
+		To:       generated.EstatePlot{X: zone.ToX, Y: zone.ToY},
+	}
+}
+
+// skippedAreas lists the areas the drone plan does not survey, or returns
+// nil when it surveys the whole estate.
+func skippedAreas(plan *droneplan.Plan) *[]generated.DronePlanSkippedArea {
+	if plan.SkippedPlots() == 0 {
+		return nil
+	}
+
+	areas := []generated.DronePlanSkippedArea{}
+	add := func(area droneplan.Area, reason generated.DronePlanSkippedAreaReason) {
+		areas = append(areas, generated.DronePlanSkippedArea{
+			From:   generated.EstatePlot{X: area.From.X, Y: area.From.Y},
+			To:     generated.EstatePlot{X: area.To.X, Y: area.To.Y},
+			Reason: reason,
+		})
+	}
+	for _, zone := range plan.NoFlyZones() {
+		add(zone, generated.NoFly)
+	}
+	for _, plot := range plan.SkippedAboveCeiling() {
+		add(droneplan.Area{From: plot, To: plot}, generated.AboveCeiling)
+	}
+	for _, area := range plan.Unreachable() {
+		add(area, generated.Unreachable)
+	}
+	return &areas
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+// maxObstacleHeight is the height in metres of the tallest obstacle that
+// can stand on an estate.
+const maxObstacleHeight = 300
+
+// obstacleResponse converts a stored obstacle to its API representation.
+func obstacleResponse(obstacle repository.Obstacle) generated.Obstacle {
+	return generated.Obstacle{
+		Id:       obstacle.Id,
+		EstateId: obstacle.EstateId,
+		X:        obstacle.X,
+		Y:        obstacle.Y,
+		Height:   obstacle.Height,
+		Kind:     obstacle.Kind,
+	}
+}
@@ -1,6 +1,9 @@
 package handler
 
-import "github.com/pebruwantoro/technical-test-sawitpro/repository"
+import (
+	"github.com/google/uuid"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
 
 type Server struct {
 	Repository repository.RepositoryInterface
@@ -15,3 +18,10 @@ func NewServer(opts NewServerOptions) *Server {
 		Repository: opts.Repository,
 	}
 }
+
+// validId reports whether the id is a UUID, the form every id of the
+// repository takes.
+func validId(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
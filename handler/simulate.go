@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+const (
+	// maxTreeHeight is the height in metres of the tallest tree that can
+	// grow on an estate.
+	maxTreeHeight = 30
+	// maxSimulatedChanges is the most tree changes a simulation may apply.
+	maxSimulatedChanges = 1000
+)
+
+// simulateTrees applies the hypothetical changes of a simulation to the
+// trees of the estate: the trees are removed first, then their heights are
+// changed, then the new trees are planted.
+func simulateTrees(estate repository.Estate, layout estateLayout, req generated.SimulateDronePlanRequest) ([]repository.EstateTree, error) {
+	var add []generated.CreateTreeRequest
+	var remove []string
+	var update []generated.SimulatedTreeHeight
+	if req.Add != nil {
+		add = *req.Add
+	}
+	if req.Remove != nil {
+		remove = *req.Remove
+	}
+	if req.Update != nil {
+		update = *req.Update
+	}
+	if len(add)+len(remove)+len(update) > maxSimulatedChanges {
+		return nil, limitExceeded("", fmt.Sprintf("Simulation has more than %d changes", maxSimulatedChanges), maxSimulatedChanges)
+	}
+
+	trees := make(map[string]int, len(layout.trees))
+	occupied := make(map[droneplan.Plot]bool, len(layout.trees)+len(layout.obstacles))
+	for i, tree := range layout.trees {
+		trees[tree.Id] = i
+		occupied[droneplan.Plot{X: tree.X, Y: tree.Y}] = true
+	}
+	for _, obstacle := range layout.obstacles {
+		occupied[droneplan.Plot{X: obstacle.X, Y: obstacle.Y}] = true
+	}
+
+	removed := make(map[string]bool, len(remove))
+	for _, id := range remove {
+		i, ok := trees[id]
+		if !ok {
+			return nil, invalidField("remove", "Tree to remove is not on the estate")
+		}
+		if removed[id] {
+			return nil, invalidField("remove", "Duplicate tree to remove")
+		}
+		removed[id] = true
+		delete(occupied, droneplan.Plot{X: layout.trees[i].X, Y: layout.trees[i].Y})
+	}
+
+	heights := make(map[string]int, len(update))
+	for _, change := range update {
+		if _, ok := trees[change.Id]; !ok {
+			return nil, invalidField("update", "Tree to update is not on the estate")
+		}
+		if removed[change.Id] {
+			return nil, invalidField("update", "Tree is both removed and updated")
+		}
+		if _, ok := heights[change.Id]; ok {
+			return nil, invalidField("update", "Duplicate tree to update")
+		}
+		if change.Height < 1 || change.Height > maxTreeHeight {
+			return nil, badRequest(generated.InvalidHeight, "Invalid Height").withField("update")
+		}
+		heights[change.Id] = change.Height
+	}
+
+	simulated := make([]repository.EstateTree, 0, len(layout.trees)-len(removed)+len(add))
+	for _, tree := range layout.trees {
+		if removed[tree.Id] {
+			continue
+		}
+		if height, ok := heights[tree.Id]; ok {
+			tree.Height = height
+		}
+		simulated = append(simulated, tree)
+	}
+
+	for _, tree := range add {
+		if tree.X <= 0 || tree.Y <= 0 || tree.X > estate.Width || tree.Y > estate.Length {
+			return nil, badRequest(generated.OutOfBounds, "Tree is outside the estate").withField("add")
+		}
+		if tree.Height < 1 || tree.Height > maxTreeHeight {
+			return nil, badRequest(generated.InvalidHeight, "Invalid Height").withField("add")
+		}
+		plot := droneplan.Plot{X: tree.X, Y: tree.Y}
+		if occupied[plot] {
+			return nil, invalidField("add", "Plot already holds a tree or an obstacle")
+		}
+		occupied[plot] = true
+		simulated = append(simulated, repository.EstateTree{
+			EstateId:        estate.Id,
+			X:               tree.X,
+			Y:               tree.Y,
+			Height:          tree.Height,
+			NeedsInspection: tree.NeedsInspection != nil && *tree.NeedsInspection,
+		})
+	}
+
+	return simulated, nil
+}
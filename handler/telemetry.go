@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/droneplan"
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+const (
+	// maxTelemetrySamples is the most samples one flight log may hold.
+	maxTelemetrySamples = 500000
+	// maxReportedDeviations is the most missed plots and altitude
+	// violations listed in a telemetry report.
+	maxReportedDeviations = 100
+)
+
+var (
+	errUnsupportedTelemetry = newError(http.StatusUnsupportedMediaType, generated.UnsupportedMediaType, "Flight log must be text/csv or application/x-ndjson")
+	errUnreachableEstate    = badRequest(generated.PlanUnreachable, "No-fly zones make the estate unreachable")
+)
+
+// telemetryLine is one line of a JSON lines flight log.
+type telemetryLine struct {
+	Timestamp *time.Time `json:"timestamp"`
+	X         *float64   `json:"x"`
+	Y         *float64   `json:"y"`
+	Altitude  *float64   `json:"altitude"`
+}
+
+// parseTelemetry reads a CSV or JSON lines flight log and returns its
+// samples sorted by time.
+func parseTelemetry(contentType string, body io.Reader) ([]repository.TelemetrySample, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errUnsupportedTelemetry
+	}
+
+	var samples []repository.TelemetrySample
+	switch mediaType {
+	case "text/csv":
+		samples, err = parseTelemetryCSV(body)
+	case "application/x-ndjson", "application/jsonl":
+		samples, err = parseTelemetryJSONLines(body)
+	default:
+		return nil, errUnsupportedTelemetry
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(samples) == 0 {
+		return nil, badRequest(generated.InvalidRequestBody, "Flight log is empty")
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].RecordedAt.Before(samples[j].RecordedAt)
+	})
+
+	return samples, nil
+}
+
+func parseTelemetryCSV(body io.Reader) ([]repository.TelemetrySample, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	samples := []repository.TelemetrySample{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, badRequest(generated.InvalidRequestBody, fmt.Sprintf("Invalid flight log line %d", line)).withDetail("line", line)
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "timestamp") {
+			continue
+		}
+
+		if len(samples) == maxTelemetrySamples {
+			return nil, limitExceeded("", fmt.Sprintf("Flight log has more than %d samples", maxTelemetrySamples), maxTelemetrySamples)
+		}
+
+		timestamp, err := time.Parse(time.RFC3339Nano, record[0])
+		if err != nil {
+			return nil, badRequest(generated.InvalidRequestBody, fmt.Sprintf("Invalid timestamp on flight log line %d", line)).withDetail("line", line)
+		}
+
+		values := [3]float64{}
+		for i := range values {
+			values[i], err = strconv.ParseFloat(record[i+1], 64)
+			if err != nil {
+				return nil, badRequest(generated.InvalidRequestBody, fmt.Sprintf("Invalid position on flight log line %d", line)).withDetail("line", line)
+			}
+		}
+
+		samples = append(samples, repository.TelemetrySample{
+			RecordedAt: timestamp,
+			X:          values[0],
+			Y:          values[1],
+			Altitude:   values[2],
+		})
+	}
+}
+
+func parseTelemetryJSONLines(body io.Reader) ([]repository.TelemetrySample, error) {
+	scanner := bufio.NewScanner(body)
+
+	samples := []repository.TelemetrySample{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if len(samples) == maxTelemetrySamples {
+			return nil, limitExceeded("", fmt.Sprintf("Flight log has more than %d samples", maxTelemetrySamples), maxTelemetrySamples)
+		}
+
+		var value telemetryLine
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil, badRequest(generated.InvalidRequestBody, fmt.Sprintf("Invalid flight log line %d", line)).withDetail("line", line)
+		}
+
+		if value.Timestamp == nil || value.X == nil || value.Y == nil || value.Altitude == nil {
+			return nil, badRequest(generated.InvalidRequestBody, fmt.Sprintf("Flight log line %d needs timestamp, x, y and altitude", line)).withDetail("line", line)
+		}
+
+		samples = append(samples, repository.TelemetrySample{
+			RecordedAt: *value.Timestamp,
+			X:          *value.X,
+			Y:          *value.Y,
+			Altitude:   *value.Altitude,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, badRequest(generated.InvalidRequestBody, "Invalid flight log")
+	}
+
+	return samples, nil
+}
+
+// telemetryReport compares the flight log of a mission against the drone
+// plan. layoutChanged tells that the plan is not the one the mission was
+// scheduled with.
+func telemetryReport(mission repository.Mission, plan *droneplan.Plan, layoutChanged bool, samples []repository.TelemetrySample) generated.TelemetryReport {
+	planSamples := make([]droneplan.Sample, 0, len(samples))
+	for _, sample := range samples {
+		planSamples = append(planSamples, droneplan.Sample{
+			Time:     sample.RecordedAt,
+			X:        sample.X,
+			Y:        sample.Y,
+			Altitude: sample.Altitude,
+		})
+	}
+
+	deviations := plan.Compare(planSamples, maxReportedDeviations)
+
+	report := generated.TelemetryReport{
+		SampleCount:            len(samples),
+		PlannedDistance:        mission.PlannedDistance,
+		ActualDistance:         deviations.Distance,
+		ExtraDistance:          deviations.Distance - float64(mission.PlannedDistance),
+		MissedPlotCount:        deviations.MissedPlotCount,
+		MissedPlots:            make([]generated.EstatePlot, 0, len(deviations.MissedPlots)),
+		AltitudeViolationCount: deviations.ViolationCount,
+		AltitudeViolations:     make([]generated.AltitudeViolation, 0, len(deviations.Violations)),
+		LayoutChanged:          layoutChanged,
+	}
+	for _, plot := range deviations.MissedPlots {
+		report.MissedPlots = append(report.MissedPlots, generated.EstatePlot{X: plot.X, Y: plot.Y})
+	}
+	for _, violation := range deviations.Violations {
+		report.AltitudeViolations = append(report.AltitudeViolations, generated.AltitudeViolation{
+			Timestamp:        violation.Time,
+			X:                violation.X,
+			Y:                violation.Y,
+			Altitude:         violation.Altitude,
+			RequiredAltitude: violation.Required,
+		})
+	}
+
+	return report
+}
+
+// missionPlan builds the drone plan of a mission from the estate layout as
+// it is now, with the clearance of the mission drone. It also reports
+// whether the layout or the clearance changed since the mission was
+// scheduled, in which case the plan is not the one the mission was planned
+// on.
+func (s *Server) missionPlan(ctx context.Context, mission repository.Mission) (plan *droneplan.Plan, layoutChanged bool, err error) {
+	estate, err := s.Repository.GetEstateById(ctx, mission.EstateId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	drone, err := s.Repository.GetDroneById(ctx, mission.DroneId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	layout, err := s.loadEstateLayout(ctx, mission.EstateId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	opts := dronePlanOptions(estate, layout, drone.Clearance)
+	plan = droneplan.New(opts)
+	if !plan.Reachable() {
+		return nil, false, errUnreachableEstate
+	}
+
+	return plan, layoutDigest(opts) != mission.LayoutDigest, nil
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pebruwantoro/technical-test-sawitpro/generated"
+	"github.com/pebruwantoro/technical-test-sawitpro/repository"
+)
+
+const (
+	// defaultTreePage and maxTreePage are the trees listed in one page.
+	defaultTreePage = 100
+	maxTreePage     = 1000
+)
+
+// treeFilter returns the filter the request lists the trees of an estate
+// with.
+func treeFilter(id string, params generated.GetEstateIdTreesParams) (repository.TreeFilter, error) {
+	filter := repository.TreeFilter{
+		EstateId:  id,
+		MinHeight: params.MinHeight,
+		MaxHeight: params.MaxHeight,
+		Sort:      repository.TreeSortPosition,
+		Limit:     defaultTreePage,
+	}
+
+	if !validTreeHeight(params.MinHeight) {
+		return filter, invalidParameter("min_height", "Invalid Height")
+	}
+	if !validTreeHeight(params.MaxHeight) {
+		return filter, invalidParameter("max_height", "Invalid Height")
+	}
+	if params.MinHeight != nil && params.MaxHeight != nil && *params.MinHeight > *params.MaxHeight {
+		return filter, invalidParameter("min_height", "min_height must not be above max_height")
+	}
+
+	box := []*int{params.MinX, params.MinY, params.MaxX, params.MaxY}
+	switch countSet(box) {
+	case 0:
+	case len(box):
+		area := repository.Area{FromX: *params.MinX, FromY: *params.MinY, ToX: *params.MaxX, ToY: *params.MaxY}
+		if area.FromX <= 0 || area.FromY <= 0 || area.FromX > area.ToX || area.FromY > area.ToY {
+			return filter, invalidParameter("min_x", "Invalid Box")
+		}
+		filter.Area = &area
+	default:
+		return filter, invalidParameter("min_x", "min_x, min_y, max_x and max_y go together")
+	}
+
+	circle := []*int{params.NearX, params.NearY, params.Radius}
+	switch countSet(circle) {
+	case 0:
+	case len(circle):
+		near := repository.Circle{X: *params.NearX, Y: *params.NearY, Radius: *params.Radius}
+		if near.X <= 0 || near.Y <= 0 {
+			return filter, invalidParameter("near_x", "Invalid Plot")
+		}
+		if near.Radius < 0 || near.Radius > maxEstateSide {
+			return filter, invalidParameter("radius", "Invalid Radius")
+		}
+		filter.Near = &near
+	default:
+		return filter, invalidParameter("near_x", "near_x, near_y and radius go together")
+	}
+
+	if params.Sort != nil {
+		switch *params.Sort {
+		case generated.Position, generated.Height:
+		case generated.Distance:
+			if filter.Near == nil {
+				return filter, invalidParameter("sort", "Sorting by distance needs near_x, near_y and radius")
+			}
+		default:
+			return filter, invalidParameter("sort", "Invalid Sort")
+		}
+		filter.Sort = string(*params.Sort)
+	}
+
+	if params.Order != nil {
+		switch *params.Order {
+		case generated.Asc:
+		case generated.Desc:
+			filter.Descending = true
+		default:
+			return filter, invalidParameter("order", "Invalid Order")
+		}
+	}
+
+	if params.Limit != nil {
+		if *params.Limit <= 0 {
+			return filter, invalidParameter("limit", "Invalid Limit")
+		}
+		if *params.Limit > maxTreePage {
+			return filter, limitExceeded("limit", fmt.Sprintf("Page has more than %d trees", maxTreePage), maxTreePage)
+		}
+		filter.Limit = *params.Limit
+	}
+
+	if params.Cursor != nil {
+		after, ok := parseTreeCursor(filter, *params.Cursor)
+		if !ok {
+			return filter, invalidParameter("cursor", "Invalid Cursor")
+		}
+		filter.After = &after
+	}
+
+	return filter, nil
+}
+
+// validTreeHeight reports whether the height, if given, is one a tree may
+// have.
+func validTreeHeight(height *int) bool {
+	return height == nil || (*height >= 1 && *height <= maxTreeHeight)
+}
+
+// countSet counts the values that are set.
+func countSet(values []*int) (count int) {
+	for _, value := range values {
+		if value != nil {
+			count++
+		}
+	}
+	return
+}
+
+// treeKey returns the place of the tree in the order of the filter.
+func treeKey(filter repository.TreeFilter, tree repository.EstateTree) repository.TreeKey {
+	key := repository.TreeKey{X: tree.X, Y: tree.Y}
+	switch filter.Sort {
+	case repository.TreeSortHeight:
+		key.Key = tree.Height
+	case repository.TreeSortDistance:
+		dx, dy := tree.X-filter.Near.X, tree.Y-filter.Near.Y
+		key.Key = dx*dx + dy*dy
+	}
+	return key
+}
+
+// treeCursor returns the cursor of the page that follows the tree. The
+// cursor carries the sort and the order, so that it is not taken up by a
+// request listing the trees in another order.
+func treeCursor(filter repository.TreeFilter, tree repository.EstateTree) string {
+	key := treeKey(filter, tree)
+	cursor := fmt.Sprintf("%s:%t:%d:%d:%d", filter.Sort, filter.Descending, key.Key, key.X, key.Y)
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+// parseTreeCursor returns the key a cursor made by treeCursor carries,
+// provided it was made for the sort and order of the filter.
+func parseTreeCursor(filter repository.TreeFilter, cursor string) (key repository.TreeKey, ok bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return key, false
+	}
+
+	fields := strings.Split(string(decoded), ":")
+	if len(fields) != 5 || fields[0] != filter.Sort || fields[1] != strconv.FormatBool(filter.Descending) {
+		return key, false
+	}
+
+	values := make([]int, 0, 3)
+	for _, field := range fields[2:] {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return key, false
+		}
+		values = append(values, value)
+	}
+	return repository.TreeKey{Key: values[0], X: values[1], Y: values[2]}, true
+}
+
+func treeResponse(tree repository.EstateTree) generated.Tree {
+	return generated.Tree{
+		Id:              tree.Id,
+		X:               tree.X,
+		Y:               tree.Y,
+		Height:          tree.Height,
+		Elevation:       tree.Elevation,
+		NeedsInspection: tree.NeedsInspection,
+	}
+}
@@ -2,18 +2,28 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 func (r *Repository) CreateEstate(ctx context.Context, input Estate) (result Estate, err error) {
 	var id string
 	err = r.Db.QueryRowContext(ctx, `
-		INSERT INTO estates (id, width, length)
-		VALUES ($1, $2, $3)
+		INSERT INTO estates (id, width, length, latitude, longitude, bearing, max_altitude)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		returning id;
 	`,
 		input.Id,
 		input.Width,
 		input.Length,
+		input.Latitude,
+		input.Longitude,
+		input.Bearing,
+		input.MaxAltitude,
 	).Scan(&id)
 	if err != nil {
 		return
@@ -24,18 +34,41 @@ func (r *Repository) CreateEstate(ctx context.Context, input Estate) (result Est
 	return
 }
 
+// CreateEstateTree plants a tree on a free plot of an estate. The bounds of
+// the estate are checked under a share lock of its row, so that the estate
+// cannot shrink past the plot meanwhile. It returns ErrPlotOccupied when the
+// plot already holds a tree or an obstacle and ErrOutsideEstate when it lies
+// outside the estate.
 func (r *Repository) CreateEstateTree(ctx context.Context, input EstateTree) (result EstateTree, err error) {
 	err = r.Db.QueryRowContext(ctx, `
-		INSERT INTO trees (id, estate_id, x, y, height)
-		VALUES ($1, $2, $3, $4, $5)
-		returning id;
+		WITH estate AS (
+			SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE
+		), plot AS (
+			INSERT INTO estate_plots (estate_id, x, y, occupant)
+			SELECT id, $3, $4, 'tree' FROM estate
+			returning estate_id, x, y
+		), tree AS (
+			INSERT INTO trees (id, estate_id, x, y, height, needs_inspection)
+			SELECT $1, estate_id, x, y, $5, $6 FROM plot
+			returning id, height
+		)
+		INSERT INTO tree_measurements (tree_id, height, measured_at, source)
+		SELECT id, height, now(), 'manual' FROM tree
+		returning tree_id;
 	`,
 		input.Id,
 		input.EstateId,
 		input.X,
 		input.Y,
 		input.Height,
+		input.NeedsInspection,
 	).Scan(&result.Id)
+	if isUniqueViolation(err) {
+		err = ErrPlotOccupied
+	}
+	if err == sql.ErrNoRows {
+		err = ErrOutsideEstate
+	}
 	if err != nil {
 		return
 	}
@@ -68,21 +101,141 @@ func (r *Repository) GetStatsByEstateId(ctx context.Context, id string) (result
 
 func (r *Repository) GetEstateById(ctx context.Context, id string) (result Estate, err error) {
 	err = r.Db.QueryRowContext(ctx, `
-		SELECT id, width, length FROM estates WHERE id = $1;
+		SELECT id, width, length, latitude, longitude, bearing, elevation, max_altitude FROM estates WHERE id = $1;
 	`, id).Scan(
 		&result.Id,
 		&result.Width,
 		&result.Length,
+		&result.Latitude,
+		&result.Longitude,
+		&result.Bearing,
+		&result.Elevation,
+		&result.MaxAltitude,
+	)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (r *Repository) GetEstates(ctx context.Context) (result []Estate, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT id, width, length, latitude, longitude, bearing, elevation, max_altitude FROM estates ORDER BY id;
+	`)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var estate Estate
+		err = rows.Scan(
+			&estate.Id,
+			&estate.Width,
+			&estate.Length,
+			&estate.Latitude,
+			&estate.Longitude,
+			&estate.Bearing,
+			&estate.Elevation,
+			&estate.MaxAltitude,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, estate)
+	}
+
+	return
+}
+
+// UpdateEstate changes the size, location and ceiling of an estate. It
+// returns sql.ErrNoRows when the estate does not exist and ErrOutsideBounds
+// when a tree or an obstacle would lie outside its new size. The estate row
+// is locked first. A tree or an obstacle planted meanwhile checks the bounds
+// under a share lock of the row, so it waits for the new size and is turned
+// away if it falls outside. The ground elevations outside the new size are
+// dropped.
+func (r *Repository) UpdateEstate(ctx context.Context, input Estate) (result Estate, err error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM estates WHERE id = $1 FOR UPDATE;
+	`, input.Id).Scan(&id)
+	if err != nil {
+		return
+	}
+
+	var outside bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM estate_plots WHERE estate_id = $1 AND (x > $2 OR y > $3));
+	`, input.Id, input.Width, input.Length).Scan(&outside)
+	if err != nil {
+		return
+	}
+	if outside {
+		err = ErrOutsideBounds
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE estates
+		SET width = $2, length = $3, latitude = $4, longitude = $5, bearing = $6, max_altitude = $7
+		WHERE id = $1;
+	`,
+		input.Id,
+		input.Width,
+		input.Length,
+		input.Latitude,
+		input.Longitude,
+		input.Bearing,
+		input.MaxAltitude,
 	)
 	if err != nil {
 		return
 	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM plot_elevations WHERE estate_id = $1 AND (x > $2 OR y > $3);
+	`, input.Id, input.Width, input.Length)
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+// DeleteEstate deletes an estate together with everything on it.
+func (r *Repository) DeleteEstate(ctx context.Context, id string) (err error) {
+	var deleted string
+	err = r.Db.QueryRowContext(ctx, `
+		DELETE FROM estates WHERE id = $1 returning id;
+	`, id).Scan(&deleted)
 	return
 }
 
 func (r *Repository) GetTreesByEstateId(ctx context.Context, id string) (result []EstateTree, err error) {
 	rows, err := r.Db.QueryContext(ctx, `
-        SELECT id, estate_id, x, y, height FROM trees WHERE estate_id = $1;
+        SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection
+        FROM trees t
+        JOIN estates e ON e.id = t.estate_id
+        LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y
+        WHERE t.estate_id = $1;
     `, id)
 	if err != nil {
 		return
@@ -97,6 +250,167 @@ func (r *Repository) GetTreesByEstateId(ctx context.Context, id string) (result
 			&tree.X,
 			&tree.Y,
 			&tree.Height,
+			&tree.Elevation,
+			&tree.NeedsInspection,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, tree)
+	}
+
+	return
+}
+
+// GetTreesByEstateIds returns the trees of all the given estates at once.
+func (r *Repository) GetTreesByEstateIds(ctx context.Context, ids []string) (result []EstateTree, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+        SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection
+        FROM trees t
+        JOIN estates e ON e.id = t.estate_id
+        LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y
+        WHERE t.estate_id = ANY($1);
+    `, pq.Array(ids))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tree EstateTree
+		err = rows.Scan(
+			&tree.Id,
+			&tree.EstateId,
+			&tree.X,
+			&tree.Y,
+			&tree.Height,
+			&tree.Elevation,
+			&tree.NeedsInspection,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, tree)
+	}
+
+	return
+}
+
+// GetTreesInArea returns the trees of an estate standing inside the area.
+func (r *Repository) GetTreesInArea(ctx context.Context, estateId string, area Area) (result []EstateTree, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+        SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection
+        FROM trees t
+        JOIN estates e ON e.id = t.estate_id
+        LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y
+        WHERE t.estate_id = $1 AND t.x BETWEEN $2 AND $4 AND t.y BETWEEN $3 AND $5;
+    `, estateId, area.FromX, area.FromY, area.ToX, area.ToY)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tree EstateTree
+		err = rows.Scan(
+			&tree.Id,
+			&tree.EstateId,
+			&tree.X,
+			&tree.Y,
+			&tree.Height,
+			&tree.Elevation,
+			&tree.NeedsInspection,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, tree)
+	}
+
+	return
+}
+
+// ListTrees returns a page of the trees of an estate picked by the filter.
+// The query is put together from the filters set; the area and the
+// bounding box of the circle narrow it down on the position index.
+func (r *Repository) ListTrees(ctx context.Context, filter TreeFilter) (result []EstateTree, err error) {
+	args := []interface{}{filter.EstateId}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"t.estate_id = $1"}
+	if filter.MinHeight != nil {
+		where = append(where, "t.height >= "+arg(*filter.MinHeight))
+	}
+	if filter.MaxHeight != nil {
+		where = append(where, "t.height <= "+arg(*filter.MaxHeight))
+	}
+	if area := treeBounds(filter); area != nil {
+		where = append(where,
+			fmt.Sprintf("t.x BETWEEN %s AND %s", arg(area.FromX), arg(area.ToX)),
+			fmt.Sprintf("t.y BETWEEN %s AND %s", arg(area.FromY), arg(area.ToY)),
+		)
+	}
+
+	// The squared distance outgrows INT on the largest estates.
+	var distance string
+	if filter.Near != nil {
+		x, y := arg(filter.Near.X), arg(filter.Near.Y)
+		distance = fmt.Sprintf("(t.x - %[1]s::BIGINT) * (t.x - %[1]s::BIGINT) + (t.y - %[2]s::BIGINT) * (t.y - %[2]s::BIGINT)", x, y)
+		where = append(where, fmt.Sprintf("%s <= %s::BIGINT * %[2]s::BIGINT", distance, arg(filter.Near.Radius)))
+	}
+
+	keys := []string{"t.x", "t.y"}
+	switch {
+	case filter.Sort == TreeSortHeight:
+		keys = []string{"t.height", "t.x", "t.y"}
+	case filter.Sort == TreeSortDistance && filter.Near != nil:
+		keys = []string{distance, "t.x", "t.y"}
+	}
+
+	direction, after := "ASC", ">"
+	if filter.Descending {
+		direction, after = "DESC", "<"
+	}
+	if filter.After != nil {
+		values := []string{arg(filter.After.X), arg(filter.After.Y)}
+		if len(keys) > len(values) {
+			values = append([]string{arg(filter.After.Key)}, values...)
+		}
+		where = append(where, fmt.Sprintf("(%s) %s (%s)", strings.Join(keys, ", "), after, strings.Join(values, ", ")))
+	}
+
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		order = append(order, key+" "+direction)
+	}
+
+	rows, err := r.Db.QueryContext(ctx, `
+        SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection
+        FROM trees t
+        JOIN estates e ON e.id = t.estate_id
+        LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY `+strings.Join(order, ", ")+`
+        LIMIT `+arg(filter.Limit)+`;
+    `, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tree EstateTree
+		err = rows.Scan(
+			&tree.Id,
+			&tree.EstateId,
+			&tree.X,
+			&tree.Y,
+			&tree.Height,
+			&tree.Elevation,
+			&tree.NeedsInspection,
 		)
 		if err != nil {
 			return
@@ -106,3 +420,698 @@ func (r *Repository) GetTreesByEstateId(ctx context.Context, id string) (result
 
 	return
 }
+
+// treeBounds returns the rectangle the trees picked by the filter stand in:
+// its area cut down to the bounding box of its circle. It returns nil when
+// the filter sets neither.
+func treeBounds(filter TreeFilter) *Area {
+	if filter.Near == nil {
+		return filter.Area
+	}
+
+	near := filter.Near
+	bounds := Area{
+		FromX: near.X - near.Radius,
+		FromY: near.Y - near.Radius,
+		ToX:   near.X + near.Radius,
+		ToY:   near.Y + near.Radius,
+	}
+	if area := filter.Area; area != nil {
+		bounds.FromX = max(bounds.FromX, area.FromX)
+		bounds.FromY = max(bounds.FromY, area.FromY)
+		bounds.ToX = min(bounds.ToX, area.ToX)
+		bounds.ToY = min(bounds.ToY, area.ToY)
+	}
+	return &bounds
+}
+
+// SetTreeInspection flags a tree for close inspection or clears the flag.
+// It returns sql.ErrNoRows when the tree does not exist.
+func (r *Repository) SetTreeInspection(ctx context.Context, id string, needsInspection bool) (err error) {
+	var updated string
+	err = r.Db.QueryRowContext(ctx, `
+		UPDATE trees SET needs_inspection = $2 WHERE id = $1
+		returning id;
+	`, id, needsInspection).Scan(&updated)
+	return
+}
+
+// GetTreeById returns a tree with the ground elevation of its plot.
+func (r *Repository) GetTreeById(ctx context.Context, id string) (result EstateTree, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+        SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection
+        FROM trees t
+        JOIN estates e ON e.id = t.estate_id
+        LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y
+        WHERE t.id = $1;
+    `, id).Scan(
+		&result.Id,
+		&result.EstateId,
+		&result.X,
+		&result.Y,
+		&result.Height,
+		&result.Elevation,
+		&result.NeedsInspection,
+	)
+	return
+}
+
+// RecordTreeMeasurement stores a measurement of a tree, in place of one
+// taken at the same time, and sets the height of the tree to its latest
+// measurement. It returns sql.ErrNoRows when the tree does not exist.
+func (r *Repository) RecordTreeMeasurement(ctx context.Context, input TreeMeasurement) (result TreeMeasurement, err error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Locking the tree keeps measurements recorded at the same time from
+	// leaving it with any but the latest height.
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM trees WHERE id = $1 FOR UPDATE;
+	`, input.TreeId).Scan(&id)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tree_measurements (tree_id, height, measured_at, source)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tree_id, measured_at) DO UPDATE SET height = EXCLUDED.height, source = EXCLUDED.source;
+	`,
+		input.TreeId,
+		input.Height,
+		input.MeasuredAt,
+		input.Source,
+	)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE trees SET height = (
+			SELECT height FROM tree_measurements WHERE tree_id = $1 ORDER BY measured_at DESC LIMIT 1
+		) WHERE id = $1;
+	`, input.TreeId)
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+// GetTreeMeasurements returns the measurements of a tree, oldest first.
+func (r *Repository) GetTreeMeasurements(ctx context.Context, treeId string) (result []TreeMeasurement, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT tree_id, height, measured_at, source FROM tree_measurements WHERE tree_id = $1 ORDER BY measured_at;
+	`, treeId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var measurement TreeMeasurement
+		err = rows.Scan(
+			&measurement.TreeId,
+			&measurement.Height,
+			&measurement.MeasuredAt,
+			&measurement.Source,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, measurement)
+	}
+
+	return
+}
+
+// GetEstateGrowth returns how fast the trees of an estate grow. The rate of
+// a tree is the height it gained from its first measurement to its latest
+// over the years between them, a year being 365.25 days.
+func (r *Repository) GetEstateGrowth(ctx context.Context, estateId string) (result EstateGrowth, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(AVG(rate), 0)
+		FROM (
+			SELECT ((ARRAY_AGG(m.height ORDER BY m.measured_at DESC))[1] - (ARRAY_AGG(m.height ORDER BY m.measured_at))[1])
+				/ (EXTRACT(EPOCH FROM MAX(m.measured_at) - MIN(m.measured_at)) / 31557600) AS rate
+			FROM tree_measurements m
+			JOIN trees t ON t.id = m.tree_id
+			WHERE t.estate_id = $1
+			GROUP BY m.tree_id
+			HAVING MAX(m.measured_at) > MIN(m.measured_at)
+		) growth;
+	`, estateId).Scan(
+		&result.TreeCount,
+		&result.AverageRate,
+	)
+	return
+}
+
+// ReplaceElevations sets the base ground elevation of an estate and stores
+// the plots off the base in place of any elevations uploaded before. It
+// returns sql.ErrNoRows when the estate does not exist.
+func (r *Repository) ReplaceElevations(ctx context.Context, estateId string, base int, elevations []PlotElevation) (err error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE estates SET elevation = $2 WHERE id = $1 returning id;
+	`, estateId, base).Scan(&id)
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM plot_elevations WHERE estate_id = $1;
+	`, estateId)
+	if err != nil {
+		return
+	}
+
+	if len(elevations) > 0 {
+		xs := make([]int64, 0, len(elevations))
+		ys := make([]int64, 0, len(elevations))
+		values := make([]int64, 0, len(elevations))
+		for _, elevation := range elevations {
+			xs = append(xs, int64(elevation.X))
+			ys = append(ys, int64(elevation.Y))
+			values = append(values, int64(elevation.Elevation))
+		}
+
+		// The plots go in as one statement of three arrays rather than one
+		// statement per plot, since an estate may upload hundreds of
+		// thousands of them.
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO plot_elevations (estate_id, x, y, elevation)
+			SELECT $1::UUID, x, y, elevation FROM unnest($2::INT[], $3::INT[], $4::INT[]) AS p (x, y, elevation);
+		`, estateId, pq.Array(xs), pq.Array(ys), pq.Array(values))
+		if err != nil {
+			return
+		}
+	}
+
+	err = tx.Commit()
+
+	return
+}
+
+func (r *Repository) GetElevationsByEstateId(ctx context.Context, estateId string) (result []PlotElevation, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT estate_id, x, y, elevation FROM plot_elevations WHERE estate_id = $1 ORDER BY y, x;
+	`, estateId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var elevation PlotElevation
+		err = rows.Scan(
+			&elevation.EstateId,
+			&elevation.X,
+			&elevation.Y,
+			&elevation.Elevation,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, elevation)
+	}
+
+	return
+}
+
+// CreateObstacle places an obstacle on a free plot of an estate, checking
+// the bounds like CreateEstateTree.
+func (r *Repository) CreateObstacle(ctx context.Context, input Obstacle) (result Obstacle, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		WITH estate AS (
+			SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE
+		), plot AS (
+			INSERT INTO estate_plots (estate_id, x, y, occupant)
+			SELECT id, $3, $4, 'obstacle' FROM estate
+			returning estate_id, x, y
+		)
+		INSERT INTO obstacles (id, estate_id, x, y, height, kind)
+		SELECT $1, estate_id, x, y, $5, $6 FROM plot
+		returning id;
+	`,
+		input.Id,
+		input.EstateId,
+		input.X,
+		input.Y,
+		input.Height,
+		input.Kind,
+	).Scan(&result.Id)
+	if isUniqueViolation(err) {
+		err = ErrPlotOccupied
+	}
+	if err == sql.ErrNoRows {
+		err = ErrOutsideEstate
+	}
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+func (r *Repository) GetObstaclesByEstateId(ctx context.Context, id string) (result []Obstacle, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT id, estate_id, x, y, height, kind FROM obstacles WHERE estate_id = $1;
+	`, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var obstacle Obstacle
+		err = rows.Scan(
+			&obstacle.Id,
+			&obstacle.EstateId,
+			&obstacle.X,
+			&obstacle.Y,
+			&obstacle.Height,
+			&obstacle.Kind,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, obstacle)
+	}
+
+	return
+}
+
+func (r *Repository) DeleteObstacle(ctx context.Context, id string) (err error) {
+	var deleted string
+	err = r.Db.QueryRowContext(ctx, `
+		DELETE FROM estate_plots p USING obstacles o
+		WHERE o.id = $1 AND p.estate_id = o.estate_id AND p.x = o.x AND p.y = o.y
+		returning o.id;
+	`, id).Scan(&deleted)
+	return
+}
+
+func (r *Repository) CreateNoFlyZone(ctx context.Context, input NoFlyZone) (result NoFlyZone, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		INSERT INTO no_fly_zones (id, estate_id, from_x, from_y, to_x, to_y)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		returning id;
+	`,
+		input.Id,
+		input.EstateId,
+		input.FromX,
+		input.FromY,
+		input.ToX,
+		input.ToY,
+	).Scan(&result.Id)
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+func (r *Repository) GetNoFlyZonesByEstateId(ctx context.Context, id string) (result []NoFlyZone, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT id, estate_id, from_x, from_y, to_x, to_y FROM no_fly_zones WHERE estate_id = $1 ORDER BY from_x, from_y, id;
+	`, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var zone NoFlyZone
+		err = rows.Scan(
+			&zone.Id,
+			&zone.EstateId,
+			&zone.FromX,
+			&zone.FromY,
+			&zone.ToX,
+			&zone.ToY,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, zone)
+	}
+
+	return
+}
+
+func (r *Repository) DeleteNoFlyZone(ctx context.Context, id string) (err error) {
+	var deleted string
+	err = r.Db.QueryRowContext(ctx, `
+		DELETE FROM no_fly_zones WHERE id = $1 returning id;
+	`, id).Scan(&deleted)
+	return
+}
+
+func (r *Repository) CreateDrone(ctx context.Context, input Drone) (result Drone, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		INSERT INTO drones (id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		returning id;
+	`,
+		input.Id,
+		input.Model,
+		input.CruiseSpeed,
+		input.ClimbRate,
+		input.DescentRate,
+		input.Endurance,
+		input.Clearance,
+	).Scan(&result.Id)
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+func (r *Repository) GetDrones(ctx context.Context) (result []Drone, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance FROM drones ORDER BY model, id;
+	`)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var drone Drone
+		err = rows.Scan(
+			&drone.Id,
+			&drone.Model,
+			&drone.CruiseSpeed,
+			&drone.ClimbRate,
+			&drone.DescentRate,
+			&drone.Endurance,
+			&drone.Clearance,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, drone)
+	}
+
+	return
+}
+
+func (r *Repository) GetDroneById(ctx context.Context, id string) (result Drone, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		SELECT id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance FROM drones WHERE id = $1;
+	`, id).Scan(
+		&result.Id,
+		&result.Model,
+		&result.CruiseSpeed,
+		&result.ClimbRate,
+		&result.DescentRate,
+		&result.Endurance,
+		&result.Clearance,
+	)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (r *Repository) UpdateDrone(ctx context.Context, input Drone) (result Drone, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		UPDATE drones
+		SET model = $2, cruise_speed = $3, climb_rate = $4, descent_rate = $5, endurance = $6, clearance = $7
+		WHERE id = $1
+		returning id;
+	`,
+		input.Id,
+		input.Model,
+		input.CruiseSpeed,
+		input.ClimbRate,
+		input.DescentRate,
+		input.Endurance,
+		input.Clearance,
+	).Scan(&result.Id)
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+func (r *Repository) DeleteDrone(ctx context.Context, id string) (err error) {
+	var deleted string
+	err = r.Db.QueryRowContext(ctx, `
+		DELETE FROM drones WHERE id = $1 returning id;
+	`, id).Scan(&deleted)
+	if isForeignKeyViolation(err) {
+		err = ErrDroneInUse
+	}
+	return
+}
+
+func (r *Repository) CreateMission(ctx context.Context, input Mission) (result Mission, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		INSERT INTO missions (id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, layout_digest)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		returning id;
+	`,
+		input.Id,
+		input.EstateId,
+		input.DroneId,
+		input.PlannedDistance,
+		input.TreeCount,
+		input.ScheduledAt,
+		input.Status,
+		input.LayoutDigest,
+	).Scan(&result.Id)
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+func (r *Repository) GetMissions(ctx context.Context, filter MissionFilter) (result []Mission, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, actual_distance, layout_digest
+		FROM missions
+		WHERE estate_id = $1
+			AND ($2::VARCHAR IS NULL OR status = $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR scheduled_at >= $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR scheduled_at < $4)
+		ORDER BY scheduled_at, id;
+	`,
+		filter.EstateId,
+		filter.Status,
+		filter.ScheduledFrom,
+		filter.ScheduledTo,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mission Mission
+		err = rows.Scan(
+			&mission.Id,
+			&mission.EstateId,
+			&mission.DroneId,
+			&mission.PlannedDistance,
+			&mission.TreeCount,
+			&mission.ScheduledAt,
+			&mission.Status,
+			&mission.ActualDistance,
+			&mission.LayoutDigest,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, mission)
+	}
+
+	return
+}
+
+func (r *Repository) GetMissionById(ctx context.Context, id string) (result Mission, err error) {
+	err = r.Db.QueryRowContext(ctx, `
+		SELECT id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, actual_distance, layout_digest FROM missions WHERE id = $1;
+	`, id).Scan(
+		&result.Id,
+		&result.EstateId,
+		&result.DroneId,
+		&result.PlannedDistance,
+		&result.TreeCount,
+		&result.ScheduledAt,
+		&result.Status,
+		&result.ActualDistance,
+		&result.LayoutDigest,
+	)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// UpdateMission moves a mission on from the status it was read in. The
+// mission row is locked while its status is checked, so that two updates
+// read in the same status cannot both move it. It returns sql.ErrNoRows
+// when the mission does not exist and ErrMissionMoved when it is no longer
+// in the from status.
+func (r *Repository) UpdateMission(ctx context.Context, input Mission, from string) (result Mission, err error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status FROM missions WHERE id = $1 FOR UPDATE;
+	`, input.Id).Scan(&status)
+	if err != nil {
+		return
+	}
+	if status != from {
+		err = ErrMissionMoved
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE missions
+		SET status = $2, actual_distance = $3
+		WHERE id = $1;
+	`,
+		input.Id,
+		input.Status,
+		input.ActualDistance,
+	)
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	result = input
+
+	return
+}
+
+// ReplaceTelemetry stores the flight log of a mission in place of any log
+// uploaded before. The samples are numbered in the given order.
+func (r *Repository) ReplaceTelemetry(ctx context.Context, missionId string, samples []TelemetrySample) (err error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM telemetry_samples WHERE mission_id = $1;
+	`, missionId)
+	if err != nil {
+		return
+	}
+
+	if len(samples) > 0 {
+		recordedAt := make([]string, 0, len(samples))
+		xs := make([]float64, 0, len(samples))
+		ys := make([]float64, 0, len(samples))
+		altitudes := make([]float64, 0, len(samples))
+		for _, sample := range samples {
+			recordedAt = append(recordedAt, sample.RecordedAt.Format(time.RFC3339Nano))
+			xs = append(xs, sample.X)
+			ys = append(ys, sample.Y)
+			altitudes = append(altitudes, sample.Altitude)
+		}
+
+		// Like the plot elevations, the samples go in as one statement of
+		// arrays, numbered by their place in them.
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO telemetry_samples (mission_id, seq, recorded_at, x, y, altitude)
+			SELECT $1::UUID, s.seq - 1, s.recorded_at, s.x, s.y, s.altitude
+			FROM unnest($2::TIMESTAMPTZ[], $3::DOUBLE PRECISION[], $4::DOUBLE PRECISION[], $5::DOUBLE PRECISION[])
+				WITH ORDINALITY AS s (recorded_at, x, y, altitude, seq);
+		`, missionId, pq.Array(recordedAt), pq.Array(xs), pq.Array(ys), pq.Array(altitudes))
+		if err != nil {
+			return
+		}
+	}
+
+	err = tx.Commit()
+
+	return
+}
+
+func (r *Repository) GetTelemetryByMissionId(ctx context.Context, missionId string) (result []TelemetrySample, err error) {
+	rows, err := r.Db.QueryContext(ctx, `
+		SELECT mission_id, recorded_at, x, y, altitude FROM telemetry_samples WHERE mission_id = $1 ORDER BY seq;
+	`, missionId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sample TelemetrySample
+		err = rows.Scan(
+			&sample.MissionId,
+			&sample.RecordedAt,
+			&sample.X,
+			&sample.Y,
+			&sample.Altitude,
+		)
+		if err != nil {
+			return
+		}
+		result = append(result, sample)
+	}
+
+	return
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,6 +17,14 @@ import (
 // 	mockRepo *MockRepositoryInterface
 // )
 
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
 type testCase struct {
 	name     string
 	request  interface{}
@@ -32,8 +43,8 @@ func TestCreateEstate(t *testing.T) {
 				Length: 10,
 			},
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estates (id, width, length) VALUES ($1, $2, $3) returning id;`)).
-					WithArgs("1", 10, 10).
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estates (id, width, length, latitude, longitude, bearing, max_altitude) VALUES ($1, $2, $3, $4, $5, $6, $7) returning id;`)).
+					WithArgs("1", 10, 10, nil, nil, 0.0, nil).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
 			},
 			response: Estate{
@@ -49,8 +60,8 @@ func TestCreateEstate(t *testing.T) {
 				Length: 10,
 			},
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estates (id, width, length) VALUES ($1, $2, $3) returning id;`)).
-					WithArgs("1", 10, 10).
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estates (id, width, length, latitude, longitude, bearing, max_altitude) VALUES ($1, $2, $3, $4, $5, $6, $7) returning id;`)).
+					WithArgs("1", 10, 10, nil, nil, 0.0, nil).
 					WillReturnError(fmt.Errorf("error"))
 			},
 			response: Estate{},
@@ -86,8 +97,8 @@ func TestCreateEstateTree(t *testing.T) {
 				Height:   10,
 			},
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO trees (id, estate_id, x, y, height) VALUES ($1, $2, $3, $4, $5) returning id;`)).
-					WithArgs("1", "1", 10, 10, 10).
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'tree' FROM estate returning estate_id, x, y ), tree AS ( INSERT INTO trees (id, estate_id, x, y, height, needs_inspection) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id, height ) INSERT INTO tree_measurements (tree_id, height, measured_at, source) SELECT id, height, now(), 'manual' FROM tree returning tree_id;`)).
+					WithArgs("1", "1", 10, 10, 10, false).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
 			},
 			response: EstateTree{
@@ -109,13 +120,47 @@ func TestCreateEstateTree(t *testing.T) {
 				Height:   10,
 			},
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO trees (id, estate_id, x, y, height) VALUES ($1, $2, $3, $4, $5) returning id;`)).
-					WithArgs("1", "1", 10, 10, 10).
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'tree' FROM estate returning estate_id, x, y ), tree AS ( INSERT INTO trees (id, estate_id, x, y, height, needs_inspection) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id, height ) INSERT INTO tree_measurements (tree_id, height, measured_at, source) SELECT id, height, now(), 'manual' FROM tree returning tree_id;`)).
+					WithArgs("1", "1", 10, 10, 10, false).
 					WillReturnError(fmt.Errorf("error"))
 			},
 			response: EstateTree{},
 			err:      fmt.Errorf("error"),
 		},
+		{
+			name: "Test Create Estate Tree - Plot Occupied",
+			request: EstateTree{
+				Id:       "1",
+				EstateId: "1",
+				X:        10,
+				Y:        10,
+				Height:   10,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'tree' FROM estate returning estate_id, x, y ), tree AS ( INSERT INTO trees (id, estate_id, x, y, height, needs_inspection) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id, height ) INSERT INTO tree_measurements (tree_id, height, measured_at, source) SELECT id, height, now(), 'manual' FROM tree returning tree_id;`)).
+					WithArgs("1", "1", 10, 10, 10, false).
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			response: EstateTree{},
+			err:      ErrPlotOccupied,
+		},
+		{
+			name: "Test Create Estate Tree - Outside Estate",
+			request: EstateTree{
+				Id:       "1",
+				EstateId: "1",
+				X:        10,
+				Y:        10,
+				Height:   10,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'tree' FROM estate returning estate_id, x, y ), tree AS ( INSERT INTO trees (id, estate_id, x, y, height, needs_inspection) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id, height ) INSERT INTO tree_measurements (tree_id, height, measured_at, source) SELECT id, height, now(), 'manual' FROM tree returning tree_id;`)).
+					WithArgs("1", "1", 10, 10, 10, false).
+					WillReturnRows(sqlmock.NewRows([]string{"tree_id"}))
+			},
+			response: EstateTree{},
+			err:      ErrOutsideEstate,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -188,15 +233,20 @@ func TestGetEstateById(t *testing.T) {
 			name:    "Test Get Stats By Estate Id - Success",
 			request: "1",
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, width, length FROM estates WHERE id = $1;`)).
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, width, length, latitude, longitude, bearing, elevation, max_altitude FROM estates WHERE id = $1;`)).
 					WithArgs("1").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "width", "length"}).AddRow("1", 10, 10))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "width", "length", "latitude", "longitude", "bearing", "elevation", "max_altitude"}).AddRow("1", 10, 10, -1.5, 102.1, 30.0, 120, 150))
 
 			},
 			response: Estate{
-				Id:     "1",
-				Width:  10,
-				Length: 10,
+				Id:          "1",
+				Width:       10,
+				Length:      10,
+				Latitude:    float64Ptr(-1.5),
+				Longitude:   float64Ptr(102.1),
+				Bearing:     30,
+				Elevation:   120,
+				MaxAltitude: intPtr(150),
 			},
 			err: nil,
 		},
@@ -204,7 +254,7 @@ func TestGetEstateById(t *testing.T) {
 			name:    "Test Get Stats By Estate Id - Error",
 			request: "1",
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, width, length FROM estates WHERE id = $1;`)).
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, width, length, latitude, longitude, bearing, elevation, max_altitude FROM estates WHERE id = $1;`)).
 					WithArgs("1").
 					WillReturnError(fmt.Errorf("error"))
 			},
@@ -229,18 +279,246 @@ func TestGetEstateById(t *testing.T) {
 	}
 }
 
+func TestGetEstates(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "Test Get Estates - Success",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, width, length, latitude, longitude, bearing, elevation, max_altitude FROM estates ORDER BY id;`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "width", "length", "latitude", "longitude", "bearing", "elevation", "max_altitude"}).
+						AddRow("1", 10, 10, -1.5, 102.1, 30.0, 120, 150).
+						AddRow("2", 5, 3, nil, nil, 0.0, 0, nil))
+			},
+			response: []Estate{
+				{
+					Id:          "1",
+					Width:       10,
+					Length:      10,
+					Latitude:    float64Ptr(-1.5),
+					Longitude:   float64Ptr(102.1),
+					Bearing:     30,
+					Elevation:   120,
+					MaxAltitude: intPtr(150),
+				},
+				{
+					Id:     "2",
+					Width:  5,
+					Length: 3,
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Test Get Estates - Error",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, width, length, latitude, longitude, bearing, elevation, max_altitude FROM estates ORDER BY id;`)).
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []Estate(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetEstates(context.Background())
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestUpdateEstate(t *testing.T) {
+	estate := Estate{
+		Id:          "1",
+		Width:       8,
+		Length:      6,
+		Bearing:     30,
+		MaxAltitude: intPtr(150),
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Update Estate - Success",
+			request: estate,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM estates WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM estate_plots WHERE estate_id = $1 AND (x > $2 OR y > $3));`)).
+					WithArgs("1", 8, 6).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectExec(regexp.QuoteMeta(`UPDATE estates SET width = $2, length = $3, latitude = $4, longitude = $5, bearing = $6, max_altitude = $7 WHERE id = $1;`)).
+					WithArgs("1", 8, 6, nil, nil, 30.0, 150).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectExec(regexp.QuoteMeta(`DELETE FROM plot_elevations WHERE estate_id = $1 AND (x > $2 OR y > $3);`)).
+					WithArgs("1", 8, 6).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			response: estate,
+			err:      nil,
+		},
+		{
+			name:    "Test Update Estate - Not Found",
+			request: estate,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM estates WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				m.ExpectRollback()
+			},
+			response: Estate{},
+			err:      sql.ErrNoRows,
+		},
+		{
+			name:    "Test Update Estate - Outside Bounds",
+			request: estate,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM estates WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM estate_plots WHERE estate_id = $1 AND (x > $2 OR y > $3));`)).
+					WithArgs("1", 8, 6).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			response: Estate{},
+			err:      ErrOutsideBounds,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.UpdateEstate(context.Background(), tc.request.(Estate))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestDeleteEstate(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Delete Estate - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM estates WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Delete Estate - Not Found",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM estates WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			err: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.DeleteEstate(context.Background(), tc.request.(string))
+		assert.Equal(t, err, tc.err)
+	}
+}
+
 func TestGetTreesByEstateId(t *testing.T) {
 	testCases := []testCase{
 		{
 			name:    "Test Get Stats By Estate Id - Success",
 			request: "1",
 			mockFunc: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, x, y, height FROM trees WHERE estate_id = $1;`)).
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1;`)).
 					WithArgs("1").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height"}).
-						AddRow("1", "1", 10, 10, 10).
-						AddRow("2", "1", 11, 11, 10))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "elevation", "needs_inspection"}).
+						AddRow("1", "1", 10, 10, 10, 120, false).
+						AddRow("2", "1", 11, 11, 10, 124, true))
+
+			},
+			response: []EstateTree{
+				{
+					Id:        "1",
+					EstateId:  "1",
+					X:         10,
+					Y:         10,
+					Height:    10,
+					Elevation: 120,
+				},
+				{
+					Id:              "2",
+					EstateId:        "1",
+					X:               11,
+					Y:               11,
+					Height:          10,
+					Elevation:       124,
+					NeedsInspection: true,
+				},
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetTreesByEstateId(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
 
+func TestGetTreesByEstateIds(t *testing.T) {
+	ids := []string{"1", "2"}
+
+	testCases := []testCase{
+		{
+			name:    "Test Get Trees By Estate Ids - Success",
+			request: ids,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = ANY($1);`)).
+					WithArgs(pq.Array(ids)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "elevation", "needs_inspection"}).
+						AddRow("1", "1", 10, 10, 10, 0, false).
+						AddRow("2", "2", 3, 4, 5, 0, true))
 			},
 			response: []EstateTree{
 				{
@@ -251,15 +529,80 @@ func TestGetTreesByEstateId(t *testing.T) {
 					Height:   10,
 				},
 				{
-					Id:       "2",
+					Id:              "2",
+					EstateId:        "2",
+					X:               3,
+					Y:               4,
+					Height:          5,
+					NeedsInspection: true,
+				},
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get Trees By Estate Ids - Error",
+			request: ids,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = ANY($1);`)).
+					WithArgs(pq.Array(ids)).
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []EstateTree(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetTreesByEstateIds(context.Background(), tc.request.([]string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetTreesInArea(t *testing.T) {
+	area := Area{FromX: 2, FromY: 3, ToX: 10, ToY: 12}
+
+	testCases := []testCase{
+		{
+			name:    "Test Get Trees In Area - Success",
+			request: area,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1 AND t.x BETWEEN $2 AND $4 AND t.y BETWEEN $3 AND $5;`)).
+					WithArgs("1", 2, 3, 10, 12).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "elevation", "needs_inspection"}).
+						AddRow("1", "1", 10, 10, 10, 0, false))
+			},
+			response: []EstateTree{
+				{
+					Id:       "1",
 					EstateId: "1",
-					X:        11,
-					Y:        11,
+					X:        10,
+					Y:        10,
 					Height:   10,
 				},
 			},
 			err: nil,
 		},
+		{
+			name:    "Test Get Trees In Area - Error",
+			request: area,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1 AND t.x BETWEEN $2 AND $4 AND t.y BETWEEN $3 AND $5;`)).
+					WithArgs("1", 2, 3, 10, 12).
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []EstateTree(nil),
+			err:      fmt.Errorf("error"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -272,7 +615,1312 @@ func TestGetTreesByEstateId(t *testing.T) {
 
 		tc.mockFunc(mock)
 
-		res, err := repo.GetTreesByEstateId(context.Background(), tc.request.(string))
+		res, err := repo.GetTreesInArea(context.Background(), "1", tc.request.(Area))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestReplaceElevations(t *testing.T) {
+	elevations := []PlotElevation{
+		{X: 1, Y: 1, Elevation: 104},
+		{X: 2, Y: 1, Elevation: 98},
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Replace Elevations - Success",
+			request: elevations,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE estates SET elevation = $2 WHERE id = $1 returning id;`)).
+					WithArgs("1", 100).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.ExpectExec(regexp.QuoteMeta(`DELETE FROM plot_elevations WHERE estate_id = $1;`)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectExec(regexp.QuoteMeta(`INSERT INTO plot_elevations (estate_id, x, y, elevation) SELECT $1::UUID, x, y, elevation FROM unnest($2::INT[], $3::INT[], $4::INT[]) AS p (x, y, elevation);`)).
+					WithArgs("1", pq.Array([]int64{1, 2}), pq.Array([]int64{1, 1}), pq.Array([]int64{104, 98})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Replace Elevations - Success Flat",
+			request: []PlotElevation{},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE estates SET elevation = $2 WHERE id = $1 returning id;`)).
+					WithArgs("1", 100).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.ExpectExec(regexp.QuoteMeta(`DELETE FROM plot_elevations WHERE estate_id = $1;`)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Replace Elevations - Not Found",
+			request: elevations,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE estates SET elevation = $2 WHERE id = $1 returning id;`)).
+					WithArgs("1", 100).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			err: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.ReplaceElevations(context.Background(), "1", 100, tc.request.([]PlotElevation))
+		assert.Equal(t, err, tc.err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestGetElevationsByEstateId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Get Elevations By Estate Id - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT estate_id, x, y, elevation FROM plot_elevations WHERE estate_id = $1 ORDER BY y, x;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"estate_id", "x", "y", "elevation"}).
+						AddRow("1", 2, 1, 98).
+						AddRow("1", 1, 2, 104))
+			},
+			response: []PlotElevation{
+				{EstateId: "1", X: 2, Y: 1, Elevation: 98},
+				{EstateId: "1", X: 1, Y: 2, Elevation: 104},
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get Elevations By Estate Id - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT estate_id, x, y, elevation FROM plot_elevations WHERE estate_id = $1 ORDER BY y, x;`)).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []PlotElevation(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetElevationsByEstateId(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestCreateObstacle(t *testing.T) {
+	obstacle := Obstacle{
+		Id:       "1",
+		EstateId: "1",
+		X:        2,
+		Y:        3,
+		Height:   40,
+		Kind:     "water tower",
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Create Obstacle - Success",
+			request: obstacle,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'obstacle' FROM estate returning estate_id, x, y ) INSERT INTO obstacles (id, estate_id, x, y, height, kind) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id;`)).
+					WithArgs("1", "1", 2, 3, 40, "water tower").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			response: obstacle,
+			err:      nil,
+		},
+		{
+			name:    "Test Create Obstacle - Error",
+			request: obstacle,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'obstacle' FROM estate returning estate_id, x, y ) INSERT INTO obstacles (id, estate_id, x, y, height, kind) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id;`)).
+					WithArgs("1", "1", 2, 3, 40, "water tower").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: Obstacle{},
+			err:      fmt.Errorf("error"),
+		},
+		{
+			name:    "Test Create Obstacle - Plot Occupied",
+			request: obstacle,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'obstacle' FROM estate returning estate_id, x, y ) INSERT INTO obstacles (id, estate_id, x, y, height, kind) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id;`)).
+					WithArgs("1", "1", 2, 3, 40, "water tower").
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			response: Obstacle{},
+			err:      ErrPlotOccupied,
+		},
+		{
+			name:    "Test Create Obstacle - Outside Estate",
+			request: obstacle,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`WITH estate AS ( SELECT id FROM estates WHERE id = $2 AND $3 <= width AND $4 <= length FOR SHARE ), plot AS ( INSERT INTO estate_plots (estate_id, x, y, occupant) SELECT id, $3, $4, 'obstacle' FROM estate returning estate_id, x, y ) INSERT INTO obstacles (id, estate_id, x, y, height, kind) SELECT $1, estate_id, x, y, $5, $6 FROM plot returning id;`)).
+					WithArgs("1", "1", 2, 3, 40, "water tower").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			response: Obstacle{},
+			err:      ErrOutsideEstate,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.CreateObstacle(context.Background(), tc.request.(Obstacle))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetObstaclesByEstateId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Get Obstacles By Estate Id - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, x, y, height, kind FROM obstacles WHERE estate_id = $1;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "kind"}).
+						AddRow("1", "1", 2, 3, 40, "water tower"))
+			},
+			response: []Obstacle{
+				{
+					Id:       "1",
+					EstateId: "1",
+					X:        2,
+					Y:        3,
+					Height:   40,
+					Kind:     "water tower",
+				},
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get Obstacles By Estate Id - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, x, y, height, kind FROM obstacles WHERE estate_id = $1;`)).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []Obstacle(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetObstaclesByEstateId(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestSetTreeInspection(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Set Tree Inspection - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE trees SET needs_inspection = $2 WHERE id = $1 returning id;`)).
+					WithArgs("1", true).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Set Tree Inspection - Not Found",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE trees SET needs_inspection = $2 WHERE id = $1 returning id;`)).
+					WithArgs("1", true).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			err: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.SetTreeInspection(context.Background(), tc.request.(string), true)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestDeleteObstacle(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Delete Obstacle - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM estate_plots p USING obstacles o WHERE o.id = $1 AND p.estate_id = o.estate_id AND p.x = o.x AND p.y = o.y returning o.id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Delete Obstacle - Not Found",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM estate_plots p USING obstacles o WHERE o.id = $1 AND p.estate_id = o.estate_id AND p.x = o.x AND p.y = o.y returning o.id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			err: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.DeleteObstacle(context.Background(), tc.request.(string))
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestCreateNoFlyZone(t *testing.T) {
+	zone := NoFlyZone{
+		Id:       "1",
+		EstateId: "1",
+		FromX:    2,
+		FromY:    3,
+		ToX:      4,
+		ToY:      5,
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Create No Fly Zone - Success",
+			request: zone,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO no_fly_zones (id, estate_id, from_x, from_y, to_x, to_y) VALUES ($1, $2, $3, $4, $5, $6) returning id;`)).
+					WithArgs("1", "1", 2, 3, 4, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			response: zone,
+			err:      nil,
+		},
+		{
+			name:    "Test Create No Fly Zone - Error",
+			request: zone,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO no_fly_zones (id, estate_id, from_x, from_y, to_x, to_y) VALUES ($1, $2, $3, $4, $5, $6) returning id;`)).
+					WithArgs("1", "1", 2, 3, 4, 5).
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: NoFlyZone{},
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.CreateNoFlyZone(context.Background(), tc.request.(NoFlyZone))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetNoFlyZonesByEstateId(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Get No Fly Zones By Estate Id - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, from_x, from_y, to_x, to_y FROM no_fly_zones WHERE estate_id = $1 ORDER BY from_x, from_y, id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "from_x", "from_y", "to_x", "to_y"}).
+						AddRow("1", "1", 2, 3, 4, 5))
+			},
+			response: []NoFlyZone{
+				{
+					Id:       "1",
+					EstateId: "1",
+					FromX:    2,
+					FromY:    3,
+					ToX:      4,
+					ToY:      5,
+				},
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get No Fly Zones By Estate Id - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, from_x, from_y, to_x, to_y FROM no_fly_zones WHERE estate_id = $1 ORDER BY from_x, from_y, id;`)).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []NoFlyZone(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetNoFlyZonesByEstateId(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestDeleteNoFlyZone(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Delete No Fly Zone - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM no_fly_zones WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Delete No Fly Zone - Not Found",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM no_fly_zones WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			err: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.DeleteNoFlyZone(context.Background(), tc.request.(string))
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestCreateDrone(t *testing.T) {
+	drone := Drone{
+		Id:          "1",
+		Model:       "Survey X4",
+		CruiseSpeed: 12,
+		ClimbRate:   3,
+		DescentRate: 2,
+		Endurance:   20000,
+		Clearance:   2,
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Create Drone - Success",
+			request: drone,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO drones (id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance) VALUES ($1, $2, $3, $4, $5, $6, $7) returning id;`)).
+					WithArgs("1", "Survey X4", 12.0, 3.0, 2.0, 20000, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			response: drone,
+			err:      nil,
+		},
+		{
+			name:    "Test Create Drone - Error",
+			request: drone,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO drones (id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance) VALUES ($1, $2, $3, $4, $5, $6, $7) returning id;`)).
+					WithArgs("1", "Survey X4", 12.0, 3.0, 2.0, 20000, 2).
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: Drone{},
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.CreateDrone(context.Background(), tc.request.(Drone))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetDrones(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "Test Get Drones - Success",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance FROM drones ORDER BY model, id;`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "model", "cruise_speed", "climb_rate", "descent_rate", "endurance", "clearance"}).
+						AddRow("1", "Survey X4", 12.0, 3.0, 2.0, 20000, 2).
+						AddRow("2", "Survey X8", 15.0, 4.0, 3.0, 30000, 1))
+			},
+			response: []Drone{
+				{
+					Id:          "1",
+					Model:       "Survey X4",
+					CruiseSpeed: 12,
+					ClimbRate:   3,
+					DescentRate: 2,
+					Endurance:   20000,
+					Clearance:   2,
+				},
+				{
+					Id:          "2",
+					Model:       "Survey X8",
+					CruiseSpeed: 15,
+					ClimbRate:   4,
+					DescentRate: 3,
+					Endurance:   30000,
+					Clearance:   1,
+				},
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetDrones(context.Background())
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetDroneById(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Get Drone By Id - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance FROM drones WHERE id = $1;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "model", "cruise_speed", "climb_rate", "descent_rate", "endurance", "clearance"}).
+						AddRow("1", "Survey X4", 12.0, 3.0, 2.0, 20000, 2))
+			},
+			response: Drone{
+				Id:          "1",
+				Model:       "Survey X4",
+				CruiseSpeed: 12,
+				ClimbRate:   3,
+				DescentRate: 2,
+				Endurance:   20000,
+				Clearance:   2,
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get Drone By Id - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, model, cruise_speed, climb_rate, descent_rate, endurance, clearance FROM drones WHERE id = $1;`)).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: Drone{},
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetDroneById(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestUpdateDrone(t *testing.T) {
+	drone := Drone{
+		Id:          "1",
+		Model:       "Survey X4",
+		CruiseSpeed: 12,
+		ClimbRate:   3,
+		DescentRate: 2,
+		Endurance:   20000,
+		Clearance:   2,
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Update Drone - Success",
+			request: drone,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE drones SET model = $2, cruise_speed = $3, climb_rate = $4, descent_rate = $5, endurance = $6, clearance = $7 WHERE id = $1 returning id;`)).
+					WithArgs("1", "Survey X4", 12.0, 3.0, 2.0, 20000, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			response: drone,
+			err:      nil,
+		},
+		{
+			name:    "Test Update Drone - Not Found",
+			request: drone,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`UPDATE drones SET model = $2, cruise_speed = $3, climb_rate = $4, descent_rate = $5, endurance = $6, clearance = $7 WHERE id = $1 returning id;`)).
+					WithArgs("1", "Survey X4", 12.0, 3.0, 2.0, 20000, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			response: Drone{},
+			err:      sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.UpdateDrone(context.Background(), tc.request.(Drone))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestDeleteDrone(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:    "Test Delete Drone - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM drones WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Delete Drone - Not Found",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM drones WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			err: sql.ErrNoRows,
+		},
+		{
+			name:    "Test Delete Drone - In Use",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`DELETE FROM drones WHERE id = $1 returning id;`)).
+					WithArgs("1").
+					WillReturnError(&pq.Error{Code: "23503"})
+			},
+			err: ErrDroneInUse,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.DeleteDrone(context.Background(), tc.request.(string))
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestCreateMission(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	mission := Mission{
+		Id:              "1",
+		EstateId:        "1",
+		DroneId:         "1",
+		PlannedDistance: 1042,
+		TreeCount:       2,
+		ScheduledAt:     scheduledAt,
+		Status:          MissionStatusPlanned,
+		LayoutDigest:    "digest",
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Create Mission - Success",
+			request: mission,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO missions (id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, layout_digest) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) returning id;`)).
+					WithArgs("1", "1", "1", 1042, 2, scheduledAt, "planned", "digest").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+			},
+			response: mission,
+			err:      nil,
+		},
+		{
+			name:    "Test Create Mission - Error",
+			request: mission,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`INSERT INTO missions (id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, layout_digest) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) returning id;`)).
+					WithArgs("1", "1", "1", 1042, 2, scheduledAt, "planned", "digest").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: Mission{},
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.CreateMission(context.Background(), tc.request.(Mission))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetMissions(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	status := MissionStatusCompleted
+
+	testCases := []testCase{
+		{
+			name: "Test Get Missions - Success",
+			request: MissionFilter{
+				EstateId:      "1",
+				Status:        &status,
+				ScheduledFrom: &scheduledAt,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, actual_distance, layout_digest FROM missions WHERE estate_id = $1 AND ($2::VARCHAR IS NULL OR status = $2) AND ($3::TIMESTAMPTZ IS NULL OR scheduled_at >= $3) AND ($4::TIMESTAMPTZ IS NULL OR scheduled_at < $4) ORDER BY scheduled_at, id;`)).
+					WithArgs("1", &status, &scheduledAt, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "drone_id", "planned_distance", "tree_count", "scheduled_at", "status", "actual_distance", "layout_digest"}).
+						AddRow("1", "1", "1", 1042, 2, scheduledAt, "completed", 1050, "digest"))
+			},
+			response: []Mission{
+				{
+					Id:              "1",
+					EstateId:        "1",
+					DroneId:         "1",
+					PlannedDistance: 1042,
+					TreeCount:       2,
+					ScheduledAt:     scheduledAt,
+					Status:          MissionStatusCompleted,
+					ActualDistance:  intPtr(1050),
+					LayoutDigest:    "digest",
+				},
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetMissions(context.Background(), tc.request.(MissionFilter))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetMissionById(t *testing.T) {
+	scheduledAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name:    "Test Get Mission By Id - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, actual_distance, layout_digest FROM missions WHERE id = $1;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "drone_id", "planned_distance", "tree_count", "scheduled_at", "status", "actual_distance", "layout_digest"}).
+						AddRow("1", "1", "1", 1042, 2, scheduledAt, "planned", nil, "digest"))
+			},
+			response: Mission{
+				Id:              "1",
+				EstateId:        "1",
+				DroneId:         "1",
+				PlannedDistance: 1042,
+				TreeCount:       2,
+				ScheduledAt:     scheduledAt,
+				Status:          MissionStatusPlanned,
+				LayoutDigest:    "digest",
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get Mission By Id - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id, estate_id, drone_id, planned_distance, tree_count, scheduled_at, status, actual_distance, layout_digest FROM missions WHERE id = $1;`)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			response: Mission{},
+			err:      sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetMissionById(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestUpdateMission(t *testing.T) {
+	mission := Mission{
+		Id:             "1",
+		Status:         MissionStatusCompleted,
+		ActualDistance: intPtr(1050),
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Update Mission - Success",
+			request: mission,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT status FROM missions WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("in-flight"))
+				m.ExpectExec(regexp.QuoteMeta(`UPDATE missions SET status = $2, actual_distance = $3 WHERE id = $1;`)).
+					WithArgs("1", "completed", intPtr(1050)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			response: mission,
+			err:      nil,
+		},
+		{
+			name:    "Test Update Mission - Not Found",
+			request: mission,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT status FROM missions WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"status"}))
+				m.ExpectRollback()
+			},
+			response: Mission{},
+			err:      sql.ErrNoRows,
+		},
+		{
+			// Another request aborted the mission after it was read in flight.
+			name:    "Test Update Mission - Moved",
+			request: mission,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT status FROM missions WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("aborted"))
+				m.ExpectRollback()
+			},
+			response: Mission{},
+			err:      ErrMissionMoved,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.UpdateMission(context.Background(), tc.request.(Mission), MissionStatusInFlight)
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestReplaceTelemetry(t *testing.T) {
+	recordedAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	samples := []TelemetrySample{
+		{RecordedAt: recordedAt, X: 1, Y: 1, Altitude: 0},
+		{RecordedAt: recordedAt.Add(time.Second), X: 1, Y: 1, Altitude: 6},
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Replace Telemetry - Success",
+			request: samples,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(`DELETE FROM telemetry_samples WHERE mission_id = $1;`)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectExec(regexp.QuoteMeta(`INSERT INTO telemetry_samples (mission_id, seq, recorded_at, x, y, altitude) SELECT $1::UUID, s.seq - 1, s.recorded_at, s.x, s.y, s.altitude FROM unnest($2::TIMESTAMPTZ[], $3::DOUBLE PRECISION[], $4::DOUBLE PRECISION[], $5::DOUBLE PRECISION[]) WITH ORDINALITY AS s (recorded_at, x, y, altitude, seq);`)).
+					WithArgs("1", pq.Array([]string{"2024-05-01T08:00:00Z", "2024-05-01T08:00:01Z"}), pq.Array([]float64{1, 1}), pq.Array([]float64{1, 1}), pq.Array([]float64{0, 6})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Replace Telemetry - Error",
+			request: samples,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(`DELETE FROM telemetry_samples WHERE mission_id = $1;`)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectExec(regexp.QuoteMeta(`INSERT INTO telemetry_samples (mission_id, seq, recorded_at, x, y, altitude) SELECT $1::UUID, s.seq - 1, s.recorded_at, s.x, s.y, s.altitude FROM unnest($2::TIMESTAMPTZ[], $3::DOUBLE PRECISION[], $4::DOUBLE PRECISION[], $5::DOUBLE PRECISION[]) WITH ORDINALITY AS s (recorded_at, x, y, altitude, seq);`)).
+					WithArgs("1", pq.Array([]string{"2024-05-01T08:00:00Z", "2024-05-01T08:00:01Z"}), pq.Array([]float64{1, 1}), pq.Array([]float64{1, 1}), pq.Array([]float64{0, 6})).
+					WillReturnError(fmt.Errorf("error"))
+				m.ExpectRollback()
+			},
+			err: fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		err := repo.ReplaceTelemetry(context.Background(), "1", tc.request.([]TelemetrySample))
+		assert.Equal(t, err, tc.err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestGetTelemetryByMissionId(t *testing.T) {
+	recordedAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name:    "Test Get Telemetry By Mission Id - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT mission_id, recorded_at, x, y, altitude FROM telemetry_samples WHERE mission_id = $1 ORDER BY seq;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"mission_id", "recorded_at", "x", "y", "altitude"}).
+						AddRow("1", recordedAt, 1.0, 1.0, 0.0).
+						AddRow("1", recordedAt.Add(time.Second), 1.0, 1.0, 6.0))
+			},
+			response: []TelemetrySample{
+				{MissionId: "1", RecordedAt: recordedAt, X: 1, Y: 1, Altitude: 0},
+				{MissionId: "1", RecordedAt: recordedAt.Add(time.Second), X: 1, Y: 1, Altitude: 6},
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetTelemetryByMissionId(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestListTrees(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "Test List Trees - First Page",
+			request: TreeFilter{
+				EstateId: "1",
+				Limit:    2,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1 ORDER BY t.x ASC, t.y ASC LIMIT $2;`)).
+					WithArgs("1", 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "elevation", "needs_inspection"}).
+						AddRow("1", "1", 1, 1, 10, 0, false).
+						AddRow("2", "1", 1, 2, 12, 0, true))
+			},
+			response: []EstateTree{
+				{Id: "1", EstateId: "1", X: 1, Y: 1, Height: 10},
+				{Id: "2", EstateId: "1", X: 1, Y: 2, Height: 12, NeedsInspection: true},
+			},
+			err: nil,
+		},
+		{
+			name: "Test List Trees - Height And Area By Height Descending",
+			request: TreeFilter{
+				EstateId:   "1",
+				MinHeight:  intPtr(5),
+				MaxHeight:  intPtr(20),
+				Area:       &Area{FromX: 2, FromY: 3, ToX: 10, ToY: 12},
+				Sort:       TreeSortHeight,
+				Descending: true,
+				After:      &TreeKey{Key: 15, X: 4, Y: 5},
+				Limit:      10,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1 AND t.height >= $2 AND t.height <= $3 AND t.x BETWEEN $4 AND $5 AND t.y BETWEEN $6 AND $7 AND (t.height, t.x, t.y) < ($10, $8, $9) ORDER BY t.height DESC, t.x DESC, t.y DESC LIMIT $11;`)).
+					WithArgs("1", 5, 20, 2, 10, 3, 12, 4, 5, 15, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "elevation", "needs_inspection"}).
+						AddRow("1", "1", 3, 4, 15, 0, false))
+			},
+			response: []EstateTree{
+				{Id: "1", EstateId: "1", X: 3, Y: 4, Height: 15},
+			},
+			err: nil,
+		},
+		{
+			name: "Test List Trees - Radius By Distance",
+			request: TreeFilter{
+				EstateId: "1",
+				Area:     &Area{FromX: 1, FromY: 1, ToX: 4, ToY: 10},
+				Near:     &Circle{X: 3, Y: 3, Radius: 2},
+				Sort:     TreeSortDistance,
+				After:    &TreeKey{Key: 1, X: 3, Y: 2},
+				Limit:    10,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1 AND t.x BETWEEN $2 AND $3 AND t.y BETWEEN $4 AND $5 AND (t.x - $6::BIGINT) * (t.x - $6::BIGINT) + (t.y - $7::BIGINT) * (t.y - $7::BIGINT) <= $8::BIGINT * $8::BIGINT AND ((t.x - $6::BIGINT) * (t.x - $6::BIGINT) + (t.y - $7::BIGINT) * (t.y - $7::BIGINT), t.x, t.y) > ($11, $9, $10) ORDER BY (t.x - $6::BIGINT) * (t.x - $6::BIGINT) + (t.y - $7::BIGINT) * (t.y - $7::BIGINT) ASC, t.x ASC, t.y ASC LIMIT $12;`)).
+					WithArgs("1", 1, 4, 1, 5, 3, 3, 2, 3, 2, 1, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height", "elevation", "needs_inspection"}).
+						AddRow("1", "1", 4, 3, 8, 0, false))
+			},
+			response: []EstateTree{
+				{Id: "1", EstateId: "1", X: 4, Y: 3, Height: 8},
+			},
+			err: nil,
+		},
+		{
+			name: "Test List Trees - Error",
+			request: TreeFilter{
+				EstateId: "1",
+				Limit:    2,
+			},
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT t.id, t.estate_id, t.x, t.y, t.height, COALESCE(pe.elevation, e.elevation), t.needs_inspection FROM trees t JOIN estates e ON e.id = t.estate_id LEFT JOIN plot_elevations pe ON pe.estate_id = t.estate_id AND pe.x = t.x AND pe.y = t.y WHERE t.estate_id = $1 ORDER BY t.x ASC, t.y ASC LIMIT $2;`)).
+					WithArgs("1", 2).
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []EstateTree(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.ListTrees(context.Background(), tc.request.(TreeFilter))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestRecordTreeMeasurement(t *testing.T) {
+	measurement := TreeMeasurement{
+		TreeId:     "1",
+		Height:     12,
+		MeasuredAt: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		Source:     MeasurementSourceDrone,
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Test Record Tree Measurement - Success",
+			request: measurement,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM trees WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.ExpectExec(regexp.QuoteMeta(`INSERT INTO tree_measurements (tree_id, height, measured_at, source) VALUES ($1, $2, $3, $4) ON CONFLICT (tree_id, measured_at) DO UPDATE SET height = EXCLUDED.height, source = EXCLUDED.source;`)).
+					WithArgs("1", 12, measurement.MeasuredAt, "drone").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectExec(regexp.QuoteMeta(`UPDATE trees SET height = ( SELECT height FROM tree_measurements WHERE tree_id = $1 ORDER BY measured_at DESC LIMIT 1 ) WHERE id = $1;`)).
+					WithArgs("1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			response: measurement,
+			err:      nil,
+		},
+		{
+			name:    "Test Record Tree Measurement - Not Found",
+			request: measurement,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM trees WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			response: TreeMeasurement{},
+			err:      sql.ErrNoRows,
+		},
+		{
+			name:    "Test Record Tree Measurement - Error",
+			request: measurement,
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM trees WHERE id = $1 FOR UPDATE;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+				m.ExpectExec(regexp.QuoteMeta(`INSERT INTO tree_measurements (tree_id, height, measured_at, source) VALUES ($1, $2, $3, $4) ON CONFLICT (tree_id, measured_at) DO UPDATE SET height = EXCLUDED.height, source = EXCLUDED.source;`)).
+					WithArgs("1", 12, measurement.MeasuredAt, "drone").
+					WillReturnError(fmt.Errorf("error"))
+				m.ExpectRollback()
+			},
+			response: TreeMeasurement{},
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.RecordTreeMeasurement(context.Background(), tc.request.(TreeMeasurement))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestGetTreeMeasurements(t *testing.T) {
+	measuredAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name:    "Test Get Tree Measurements - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT tree_id, height, measured_at, source FROM tree_measurements WHERE tree_id = $1 ORDER BY measured_at;`)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"tree_id", "height", "measured_at", "source"}).
+						AddRow("1", 10, measuredAt, "manual").
+						AddRow("1", 12, measuredAt.AddDate(0, 6, 0), "drone"))
+			},
+			response: []TreeMeasurement{
+				{TreeId: "1", Height: 10, MeasuredAt: measuredAt, Source: MeasurementSourceManual},
+				{TreeId: "1", Height: 12, MeasuredAt: measuredAt.AddDate(0, 6, 0), Source: MeasurementSourceDrone},
+			},
+			err: nil,
+		},
+		{
+			name:    "Test Get Tree Measurements - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT tree_id, height, measured_at, source FROM tree_measurements WHERE tree_id = $1 ORDER BY measured_at;`)).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: []TreeMeasurement(nil),
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetTreeMeasurements(context.Background(), tc.request.(string))
+		assert.Equal(t, res, tc.response)
+		assert.Equal(t, err, tc.err)
+	}
+}
+
+func TestGetEstateGrowth(t *testing.T) {
+	query := `SELECT COUNT(*), COALESCE(AVG(rate), 0) FROM ( SELECT ((ARRAY_AGG(m.height ORDER BY m.measured_at DESC))[1] - (ARRAY_AGG(m.height ORDER BY m.measured_at))[1]) / (EXTRACT(EPOCH FROM MAX(m.measured_at) - MIN(m.measured_at)) / 31557600) AS rate FROM tree_measurements m JOIN trees t ON t.id = m.tree_id WHERE t.estate_id = $1 GROUP BY m.tree_id HAVING MAX(m.measured_at) > MIN(m.measured_at) ) growth;`
+
+	testCases := []testCase{
+		{
+			name:    "Test Get Estate Growth - Success",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("1").
+					WillReturnRows(sqlmock.NewRows([]string{"count", "coalesce"}).AddRow(3, 1.5))
+			},
+			response: EstateGrowth{TreeCount: 3, AverageRate: 1.5},
+			err:      nil,
+		},
+		{
+			name:    "Test Get Estate Growth - Error",
+			request: "1",
+			mockFunc: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs("1").
+					WillReturnError(fmt.Errorf("error"))
+			},
+			response: EstateGrowth{},
+			err:      fmt.Errorf("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		repo := &Repository{
+			Db: db,
+		}
+
+		tc.mockFunc(mock)
+
+		res, err := repo.GetEstateGrowth(context.Background(), tc.request.(string))
 		assert.Equal(t, res, tc.response)
 		assert.Equal(t, err, tc.err)
 	}
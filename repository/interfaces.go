@@ -11,5 +11,35 @@ type RepositoryInterface interface {
 	CreateEstateTree(ctx context.Context, input EstateTree) (result EstateTree, err error)
 	GetStatsByEstateId(ctx context.Context, id string) (result StatsEstate, err error)
 	GetEstateById(ctx context.Context, id string) (result Estate, err error)
+	GetEstates(ctx context.Context) (result []Estate, err error)
+	UpdateEstate(ctx context.Context, input Estate) (result Estate, err error)
+	DeleteEstate(ctx context.Context, id string) (err error)
 	GetTreesByEstateId(ctx context.Context, id string) (result []EstateTree, err error)
+	GetTreesByEstateIds(ctx context.Context, ids []string) (result []EstateTree, err error)
+	GetTreesInArea(ctx context.Context, estateId string, area Area) (result []EstateTree, err error)
+	ListTrees(ctx context.Context, filter TreeFilter) (result []EstateTree, err error)
+	SetTreeInspection(ctx context.Context, id string, needsInspection bool) (err error)
+	GetTreeById(ctx context.Context, id string) (result EstateTree, err error)
+	RecordTreeMeasurement(ctx context.Context, input TreeMeasurement) (result TreeMeasurement, err error)
+	GetTreeMeasurements(ctx context.Context, treeId string) (result []TreeMeasurement, err error)
+	GetEstateGrowth(ctx context.Context, estateId string) (result EstateGrowth, err error)
+	ReplaceElevations(ctx context.Context, estateId string, base int, elevations []PlotElevation) (err error)
+	GetElevationsByEstateId(ctx context.Context, estateId string) (result []PlotElevation, err error)
+	CreateObstacle(ctx context.Context, input Obstacle) (result Obstacle, err error)
+	GetObstaclesByEstateId(ctx context.Context, id string) (result []Obstacle, err error)
+	DeleteObstacle(ctx context.Context, id string) (err error)
+	CreateNoFlyZone(ctx context.Context, input NoFlyZone) (result NoFlyZone, err error)
+	GetNoFlyZonesByEstateId(ctx context.Context, id string) (result []NoFlyZone, err error)
+	DeleteNoFlyZone(ctx context.Context, id string) (err error)
+	CreateDrone(ctx context.Context, input Drone) (result Drone, err error)
+	GetDrones(ctx context.Context) (result []Drone, err error)
+	GetDroneById(ctx context.Context, id string) (result Drone, err error)
+	UpdateDrone(ctx context.Context, input Drone) (result Drone, err error)
+	DeleteDrone(ctx context.Context, id string) (err error)
+	CreateMission(ctx context.Context, input Mission) (result Mission, err error)
+	GetMissions(ctx context.Context, filter MissionFilter) (result []Mission, err error)
+	GetMissionById(ctx context.Context, id string) (result Mission, err error)
+	UpdateMission(ctx context.Context, input Mission, from string) (result Mission, err error)
+	ReplaceTelemetry(ctx context.Context, missionId string, samples []TelemetrySample) (err error)
+	GetTelemetryByMissionId(ctx context.Context, missionId string) (result []TelemetrySample, err error)
 }
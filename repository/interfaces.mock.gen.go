@@ -40,6 +40,21 @@ func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder
 	return m.recorder
 }
 
+// CreateDrone mocks base method.
+func (m *MockRepositoryInterface) CreateDrone(ctx context.Context, input Drone) (Drone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDrone", ctx, input)
+	ret0, _ := ret[0].(Drone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDrone indicates an expected call of CreateDrone.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateDrone(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDrone", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateDrone), ctx, input)
+}
+
 // CreateEstate mocks base method.
 func (m *MockRepositoryInterface) CreateEstate(ctx context.Context, input Estate) (Estate, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +85,152 @@ func (mr *MockRepositoryInterfaceMockRecorder) CreateEstateTree(ctx, input any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEstateTree", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateEstateTree), ctx, input)
 }
 
+// CreateMission mocks base method.
+func (m *MockRepositoryInterface) CreateMission(ctx context.Context, input Mission) (Mission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMission", ctx, input)
+	ret0, _ := ret[0].(Mission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMission indicates an expected call of CreateMission.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateMission(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMission", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateMission), ctx, input)
+}
+
+// CreateNoFlyZone mocks base method.
+func (m *MockRepositoryInterface) CreateNoFlyZone(ctx context.Context, input NoFlyZone) (NoFlyZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNoFlyZone", ctx, input)
+	ret0, _ := ret[0].(NoFlyZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNoFlyZone indicates an expected call of CreateNoFlyZone.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateNoFlyZone(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNoFlyZone", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateNoFlyZone), ctx, input)
+}
+
+// CreateObstacle mocks base method.
+func (m *MockRepositoryInterface) CreateObstacle(ctx context.Context, input Obstacle) (Obstacle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateObstacle", ctx, input)
+	ret0, _ := ret[0].(Obstacle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateObstacle indicates an expected call of CreateObstacle.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateObstacle(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateObstacle", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateObstacle), ctx, input)
+}
+
+// DeleteDrone mocks base method.
+func (m *MockRepositoryInterface) DeleteDrone(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDrone", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDrone indicates an expected call of DeleteDrone.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteDrone(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDrone", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteDrone), ctx, id)
+}
+
+// DeleteEstate mocks base method.
+func (m *MockRepositoryInterface) DeleteEstate(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEstate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEstate indicates an expected call of DeleteEstate.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteEstate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteEstate), ctx, id)
+}
+
+// DeleteNoFlyZone mocks base method.
+func (m *MockRepositoryInterface) DeleteNoFlyZone(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNoFlyZone", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNoFlyZone indicates an expected call of DeleteNoFlyZone.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteNoFlyZone(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNoFlyZone", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteNoFlyZone), ctx, id)
+}
+
+// DeleteObstacle mocks base method.
+func (m *MockRepositoryInterface) DeleteObstacle(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteObstacle", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteObstacle indicates an expected call of DeleteObstacle.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteObstacle(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObstacle", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteObstacle), ctx, id)
+}
+
+// GetDroneById mocks base method.
+func (m *MockRepositoryInterface) GetDroneById(ctx context.Context, id string) (Drone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDroneById", ctx, id)
+	ret0, _ := ret[0].(Drone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDroneById indicates an expected call of GetDroneById.
+func (mr *MockRepositoryInterfaceMockRecorder) GetDroneById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDroneById", reflect.TypeOf((*MockRepositoryInterface)(nil).GetDroneById), ctx, id)
+}
+
+// GetDrones mocks base method.
+func (m *MockRepositoryInterface) GetDrones(ctx context.Context) ([]Drone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDrones", ctx)
+	ret0, _ := ret[0].([]Drone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDrones indicates an expected call of GetDrones.
+func (mr *MockRepositoryInterfaceMockRecorder) GetDrones(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDrones", reflect.TypeOf((*MockRepositoryInterface)(nil).GetDrones), ctx)
+}
+
+// GetElevationsByEstateId mocks base method.
+func (m *MockRepositoryInterface) GetElevationsByEstateId(ctx context.Context, estateId string) ([]PlotElevation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetElevationsByEstateId", ctx, estateId)
+	ret0, _ := ret[0].([]PlotElevation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetElevationsByEstateId indicates an expected call of GetElevationsByEstateId.
+func (mr *MockRepositoryInterfaceMockRecorder) GetElevationsByEstateId(ctx, estateId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetElevationsByEstateId", reflect.TypeOf((*MockRepositoryInterface)(nil).GetElevationsByEstateId), ctx, estateId)
+}
+
 // GetEstateById mocks base method.
 func (m *MockRepositoryInterface) GetEstateById(ctx context.Context, id string) (Estate, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +246,96 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetEstateById(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateById", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEstateById), ctx, id)
 }
 
+// GetEstateGrowth mocks base method.
+func (m *MockRepositoryInterface) GetEstateGrowth(ctx context.Context, estateId string) (EstateGrowth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstateGrowth", ctx, estateId)
+	ret0, _ := ret[0].(EstateGrowth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstateGrowth indicates an expected call of GetEstateGrowth.
+func (mr *MockRepositoryInterfaceMockRecorder) GetEstateGrowth(ctx, estateId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateGrowth", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEstateGrowth), ctx, estateId)
+}
+
+// GetEstates mocks base method.
+func (m *MockRepositoryInterface) GetEstates(ctx context.Context) ([]Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstates", ctx)
+	ret0, _ := ret[0].([]Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstates indicates an expected call of GetEstates.
+func (mr *MockRepositoryInterfaceMockRecorder) GetEstates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstates", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEstates), ctx)
+}
+
+// GetMissionById mocks base method.
+func (m *MockRepositoryInterface) GetMissionById(ctx context.Context, id string) (Mission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMissionById", ctx, id)
+	ret0, _ := ret[0].(Mission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMissionById indicates an expected call of GetMissionById.
+func (mr *MockRepositoryInterfaceMockRecorder) GetMissionById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMissionById", reflect.TypeOf((*MockRepositoryInterface)(nil).GetMissionById), ctx, id)
+}
+
+// GetMissions mocks base method.
+func (m *MockRepositoryInterface) GetMissions(ctx context.Context, filter MissionFilter) ([]Mission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMissions", ctx, filter)
+	ret0, _ := ret[0].([]Mission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMissions indicates an expected call of GetMissions.
+func (mr *MockRepositoryInterfaceMockRecorder) GetMissions(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMissions", reflect.TypeOf((*MockRepositoryInterface)(nil).GetMissions), ctx, filter)
+}
+
+// GetNoFlyZonesByEstateId mocks base method.
+func (m *MockRepositoryInterface) GetNoFlyZonesByEstateId(ctx context.Context, id string) ([]NoFlyZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNoFlyZonesByEstateId", ctx, id)
+	ret0, _ := ret[0].([]NoFlyZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNoFlyZonesByEstateId indicates an expected call of GetNoFlyZonesByEstateId.
+func (mr *MockRepositoryInterfaceMockRecorder) GetNoFlyZonesByEstateId(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNoFlyZonesByEstateId", reflect.TypeOf((*MockRepositoryInterface)(nil).GetNoFlyZonesByEstateId), ctx, id)
+}
+
+// GetObstaclesByEstateId mocks base method.
+func (m *MockRepositoryInterface) GetObstaclesByEstateId(ctx context.Context, id string) ([]Obstacle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObstaclesByEstateId", ctx, id)
+	ret0, _ := ret[0].([]Obstacle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObstaclesByEstateId indicates an expected call of GetObstaclesByEstateId.
+func (mr *MockRepositoryInterfaceMockRecorder) GetObstaclesByEstateId(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObstaclesByEstateId", reflect.TypeOf((*MockRepositoryInterface)(nil).GetObstaclesByEstateId), ctx, id)
+}
+
 // GetStatsByEstateId mocks base method.
 func (m *MockRepositoryInterface) GetStatsByEstateId(ctx context.Context, id string) (StatsEstate, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +351,51 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetStatsByEstateId(ctx, id any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatsByEstateId", reflect.TypeOf((*MockRepositoryInterface)(nil).GetStatsByEstateId), ctx, id)
 }
 
+// GetTelemetryByMissionId mocks base method.
+func (m *MockRepositoryInterface) GetTelemetryByMissionId(ctx context.Context, missionId string) ([]TelemetrySample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTelemetryByMissionId", ctx, missionId)
+	ret0, _ := ret[0].([]TelemetrySample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTelemetryByMissionId indicates an expected call of GetTelemetryByMissionId.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTelemetryByMissionId(ctx, missionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTelemetryByMissionId", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTelemetryByMissionId), ctx, missionId)
+}
+
+// GetTreeById mocks base method.
+func (m *MockRepositoryInterface) GetTreeById(ctx context.Context, id string) (EstateTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeById", ctx, id)
+	ret0, _ := ret[0].(EstateTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreeById indicates an expected call of GetTreeById.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTreeById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeById", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTreeById), ctx, id)
+}
+
+// GetTreeMeasurements mocks base method.
+func (m *MockRepositoryInterface) GetTreeMeasurements(ctx context.Context, treeId string) ([]TreeMeasurement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeMeasurements", ctx, treeId)
+	ret0, _ := ret[0].([]TreeMeasurement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreeMeasurements indicates an expected call of GetTreeMeasurements.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTreeMeasurements(ctx, treeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeMeasurements", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTreeMeasurements), ctx, treeId)
+}
+
 // GetTreesByEstateId mocks base method.
 func (m *MockRepositoryInterface) GetTreesByEstateId(ctx context.Context, id string) ([]EstateTree, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +410,150 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetTreesByEstateId(ctx, id any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesByEstateId", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTreesByEstateId), ctx, id)
 }
+
+// GetTreesByEstateIds mocks base method.
+func (m *MockRepositoryInterface) GetTreesByEstateIds(ctx context.Context, ids []string) ([]EstateTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreesByEstateIds", ctx, ids)
+	ret0, _ := ret[0].([]EstateTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreesByEstateIds indicates an expected call of GetTreesByEstateIds.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTreesByEstateIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesByEstateIds", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTreesByEstateIds), ctx, ids)
+}
+
+// GetTreesInArea mocks base method.
+func (m *MockRepositoryInterface) GetTreesInArea(ctx context.Context, estateId string, area Area) ([]EstateTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreesInArea", ctx, estateId, area)
+	ret0, _ := ret[0].([]EstateTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreesInArea indicates an expected call of GetTreesInArea.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTreesInArea(ctx, estateId, area any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesInArea", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTreesInArea), ctx, estateId, area)
+}
+
+// ListTrees mocks base method.
+func (m *MockRepositoryInterface) ListTrees(ctx context.Context, filter TreeFilter) ([]EstateTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrees", ctx, filter)
+	ret0, _ := ret[0].([]EstateTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrees indicates an expected call of ListTrees.
+func (mr *MockRepositoryInterfaceMockRecorder) ListTrees(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrees", reflect.TypeOf((*MockRepositoryInterface)(nil).ListTrees), ctx, filter)
+}
+
+// RecordTreeMeasurement mocks base method.
+func (m *MockRepositoryInterface) RecordTreeMeasurement(ctx context.Context, input TreeMeasurement) (TreeMeasurement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTreeMeasurement", ctx, input)
+	ret0, _ := ret[0].(TreeMeasurement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordTreeMeasurement indicates an expected call of RecordTreeMeasurement.
+func (mr *MockRepositoryInterfaceMockRecorder) RecordTreeMeasurement(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTreeMeasurement", reflect.TypeOf((*MockRepositoryInterface)(nil).RecordTreeMeasurement), ctx, input)
+}
+
+// ReplaceElevations mocks base method.
+func (m *MockRepositoryInterface) ReplaceElevations(ctx context.Context, estateId string, base int, elevations []PlotElevation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceElevations", ctx, estateId, base, elevations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceElevations indicates an expected call of ReplaceElevations.
+func (mr *MockRepositoryInterfaceMockRecorder) ReplaceElevations(ctx, estateId, base, elevations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceElevations", reflect.TypeOf((*MockRepositoryInterface)(nil).ReplaceElevations), ctx, estateId, base, elevations)
+}
+
+// ReplaceTelemetry mocks base method.
+func (m *MockRepositoryInterface) ReplaceTelemetry(ctx context.Context, missionId string, samples []TelemetrySample) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTelemetry", ctx, missionId, samples)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTelemetry indicates an expected call of ReplaceTelemetry.
+func (mr *MockRepositoryInterfaceMockRecorder) ReplaceTelemetry(ctx, missionId, samples any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTelemetry", reflect.TypeOf((*MockRepositoryInterface)(nil).ReplaceTelemetry), ctx, missionId, samples)
+}
+
+// SetTreeInspection mocks base method.
+func (m *MockRepositoryInterface) SetTreeInspection(ctx context.Context, id string, needsInspection bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTreeInspection", ctx, id, needsInspection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTreeInspection indicates an expected call of SetTreeInspection.
+func (mr *MockRepositoryInterfaceMockRecorder) SetTreeInspection(ctx, id, needsInspection any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTreeInspection", reflect.TypeOf((*MockRepositoryInterface)(nil).SetTreeInspection), ctx, id, needsInspection)
+}
+
+// UpdateDrone mocks base method.
+func (m *MockRepositoryInterface) UpdateDrone(ctx context.Context, input Drone) (Drone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDrone", ctx, input)
+	ret0, _ := ret[0].(Drone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDrone indicates an expected call of UpdateDrone.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateDrone(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDrone", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateDrone), ctx, input)
+}
+
+// UpdateEstate mocks base method.
+func (m *MockRepositoryInterface) UpdateEstate(ctx context.Context, input Estate) (Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEstate", ctx, input)
+	ret0, _ := ret[0].(Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEstate indicates an expected call of UpdateEstate.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateEstate(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateEstate), ctx, input)
+}
+
+// UpdateMission mocks base method.
+func (m *MockRepositoryInterface) UpdateMission(ctx context.Context, input Mission, from string) (Mission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMission", ctx, input, from)
+	ret0, _ := ret[0].(Mission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMission indicates an expected call of UpdateMission.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateMission(ctx, input, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMission", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateMission), ctx, input, from)
+}
@@ -3,10 +3,31 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// ErrPlotOccupied is returned when a tree or an obstacle is placed on a plot
+// that already holds one.
+var ErrPlotOccupied = errors.New("plot is already occupied")
+
+// ErrOutsideBounds is returned when an estate is resized so that a tree or
+// an obstacle would lie outside it.
+var ErrOutsideBounds = errors.New("trees or obstacles lie outside the estate")
+
+// ErrOutsideEstate is returned when a tree or an obstacle is placed on a
+// plot outside the estate, or on an estate that no longer exists.
+var ErrOutsideEstate = errors.New("plot lies outside the estate")
+
+// ErrMissionMoved is returned when a mission is updated from a status it
+// has already left.
+var ErrMissionMoved = errors.New("mission has left the status")
+
+// ErrDroneInUse is returned when a drone is deleted while missions are
+// still scheduled with it.
+var ErrDroneInUse = errors.New("drone has missions")
+
 type Repository struct {
 	Db *sql.DB
 }
@@ -24,3 +45,24 @@ func NewRepository(opts NewRepositoryOptions) *Repository {
 		Db: db,
 	}
 }
+
+// isUniqueViolation reports whether the error is a unique constraint
+// violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// IsInvalidId reports whether the error is the database rejecting an id
+// that is not a UUID.
+func IsInvalidId(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "22P02"
+}
+
+// isForeignKeyViolation reports whether the error is a foreign key
+// constraint violation.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
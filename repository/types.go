@@ -1,18 +1,113 @@
 // This file contains types that are used in the repository layer.
 package repository
 
+import "time"
+
 type Estate struct {
-	Id     string
-	Width  int
-	Length int
+	Id        string
+	Width     int
+	Length    int
+	Latitude  *float64
+	Longitude *float64
+	Bearing   float64
+	Elevation int
+	// MaxAltitude is the ceiling in metres above the ground the drone must
+	// not fly over, or nil for none.
+	MaxAltitude *int
 }
 
 type EstateTree struct {
+	Id              string
+	EstateId        string
+	X               int
+	Y               int
+	Height          int
+	Elevation       int
+	NeedsInspection bool
+}
+
+const (
+	MeasurementSourceManual = "manual"
+	MeasurementSourceDrone  = "drone"
+)
+
+// TreeMeasurement is the height a tree was measured at, at one time.
+type TreeMeasurement struct {
+	TreeId     string
+	Height     int
+	MeasuredAt time.Time
+	Source     string
+}
+
+// EstateGrowth is how fast the trees of an estate grow: the average rate
+// in metres a year of the trees measured at two times or more.
+type EstateGrowth struct {
+	TreeCount   int
+	AverageRate float64
+}
+
+// Area is a rectangle of plots, both corners included.
+type Area struct {
+	FromX int
+	FromY int
+	ToX   int
+	ToY   int
+}
+
+// Circle is the plots within Radius plots of plot (X, Y).
+type Circle struct {
+	X      int
+	Y      int
+	Radius int
+}
+
+const (
+	TreeSortPosition = "position"
+	TreeSortHeight   = "height"
+	TreeSortDistance = "distance"
+)
+
+// TreeFilter picks a page of the trees of an estate. The trees are ordered
+// by the sort key, then by position, so that every tree has its place.
+type TreeFilter struct {
+	EstateId  string
+	MinHeight *int
+	MaxHeight *int
+	// Area keeps only the trees standing inside it.
+	Area *Area
+	// Near keeps only the trees standing inside it, and is what the
+	// distance sort measures from.
+	Near       *Circle
+	Sort       string
+	Descending bool
+	// After is the key of the last tree of the previous page, or nil for
+	// the first page.
+	After *TreeKey
+	Limit int
+}
+
+// TreeKey is the place of a tree in the order of a TreeFilter: its sort
+// key and its position. Key is unused when sorting by position.
+type TreeKey struct {
+	Key int
+	X   int
+	Y   int
+}
+
+type PlotElevation struct {
+	EstateId  string
+	X         int
+	Y         int
+	Elevation int
+}
+
+type Obstacle struct {
 	Id       string
 	EstateId string
 	X        int
 	Y        int
 	Height   int
+	Kind     string
 }
 
 type StatsEstate struct {
@@ -21,3 +116,58 @@ type StatsEstate struct {
 	Min    int
 	Median float64
 }
+
+type NoFlyZone struct {
+	Id       string
+	EstateId string
+	FromX    int
+	FromY    int
+	ToX      int
+	ToY      int
+}
+
+type Drone struct {
+	Id          string
+	Model       string
+	CruiseSpeed float64
+	ClimbRate   float64
+	DescentRate float64
+	Endurance   int
+	Clearance   int
+}
+
+const (
+	MissionStatusPlanned   = "planned"
+	MissionStatusInFlight  = "in-flight"
+	MissionStatusCompleted = "completed"
+	MissionStatusAborted   = "aborted"
+)
+
+type Mission struct {
+	Id              string
+	EstateId        string
+	DroneId         string
+	PlannedDistance int
+	TreeCount       int
+	ScheduledAt     time.Time
+	Status          string
+	ActualDistance  *int
+	// LayoutDigest is the digest of everything the drone plan was built
+	// from when the mission was scheduled.
+	LayoutDigest string
+}
+
+type MissionFilter struct {
+	EstateId      string
+	Status        *string
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+}
+
+type TelemetrySample struct {
+	MissionId  string
+	RecordedAt time.Time
+	X          float64
+	Y          float64
+	Altitude   float64
+}
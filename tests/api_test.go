@@ -181,7 +181,32 @@ func getTestCases() []TestCase {
 				},
 				{
 					Request: SendRequestGetDronePlan(0),
-					Expect:  ExpectGetDronePlanOk(225),
+					Expect:  ExpectGetDronePlanOk(1082),
+				},
+			},
+		},
+		{
+			Name: "Test Get Estate Id Drone Plan - Success With Max Distance",
+			Steps: []TestCaseStep{
+				{
+					Request: SendRequestNewEstate(1, 5),
+					Expect:  ExpectNewEstateOk(),
+				},
+				{
+					Request: SendRequestNewTree(5, 2, 1),
+					Expect:  ExpectNewTreeOk(),
+				},
+				{
+					Request: SendRequestNewTree(3, 3, 1),
+					Expect:  ExpectNewTreeOk(),
+				},
+				{
+					Request: SendRequestNewTree(4, 4, 1),
+					Expect:  ExpectNewTreeOk(),
+				},
+				{
+					Request: SendRequestGetDronePlan(40),
+					Expect:  ExpectGetDronePlanWithRestOk(32, 3, 1),
 				},
 			},
 		},
@@ -321,15 +346,15 @@ func ExpectGetStatsOk(count, min, max, median int) ExpectFunc {
 	}
 }
 
-func SendRequestGetDronePlan(distance int) RequestFunc {
+func SendRequestGetDronePlan(maxDistance int) RequestFunc {
 	return func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
 		id := tc.Steps[0].Result["id"].(string)
 		var url string
 
-		if distance == 0 {
+		if maxDistance == 0 {
 			url = fmt.Sprintf("%s/estate/%s/drone-plan", ApiUrl, id)
 		} else {
-			url = fmt.Sprintf("%s/estate/%s/drone-plan?distance=%d", ApiUrl, id, distance)
+			url = fmt.Sprintf("%s/estate/%s/drone-plan?max_distance=%d", ApiUrl, id, maxDistance)
 		}
 		return http.NewRequest("GET", url, nil)
 	}
@@ -341,6 +366,13 @@ func ExpectGetDronePlanOk(distance int) ExpectFunc {
 	}
 }
 
+func ExpectGetDronePlanWithRestOk(distance, x, y int) ExpectFunc {
+	return func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any) {
+		RequireDistance(t, resp, data, distance)
+		RequireRest(t, data, x, y)
+	}
+}
+
 func RequireReturnIsUUID(t *testing.T, resp *http.Response, data map[string]any) {
 	require.Equal(t, http.StatusCreated, resp.StatusCode)
 	RequireIsUUID(t, data["id"].(string))
@@ -359,6 +391,12 @@ func RequireDistance(t *testing.T, resp *http.Response, data map[string]any, dis
 	require.Equal(t, distance, int(data["distance"].(float64)))
 }
 
+func RequireRest(t *testing.T, data map[string]any, x, y int) {
+	rest := data["rest"].(map[string]any)
+	require.Equal(t, x, int(rest["x"].(float64)))
+	require.Equal(t, y, int(rest["y"].(float64)))
+}
+
 func ExpectBadRequest() ExpectFunc {
 	return func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any) {
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)